@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,8 +16,10 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	"reviewer/internal/config"
+	"reviewer/internal/domain"
 	"reviewer/internal/handler"
 	"reviewer/internal/logger"
+	"reviewer/internal/notify"
 	"reviewer/internal/repository/postgres"
 	"reviewer/internal/service"
 	"reviewer/migrations"
@@ -42,7 +45,11 @@ func main() {
 		return
 	}
 
-	svc := service.New(repo)
+	svc := service.New(repo,
+		service.WithAbsenceReleaseThreshold(cfg.AbsenceReleaseThreshold),
+		service.WithNotifier(newNotifier(cfg, log)),
+		service.WithDigestHour(cfg.DigestHour),
+	)
 	h := handler.New(svc, log)
 	r := chi.NewRouter()
 
@@ -78,11 +85,27 @@ func main() {
 		}
 	}()
 
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	defer bgCancel()
+	if cfg.AbsenceReleaseThreshold > 0 {
+		go runAbsenceReleaser(bgCtx, svc, log)
+	}
+	if cfg.StaleReviewInterval > 0 {
+		go runStaleReviewEscalator(bgCtx, svc, log, cfg.StaleReviewInterval)
+	}
+	if cfg.NotificationInterval > 0 {
+		go runNotificationDelivery(bgCtx, svc, log, cfg.NotificationInterval)
+	}
+	if cfg.DigestInterval > 0 {
+		go runDigestSender(bgCtx, svc, log, cfg.DigestInterval)
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
 	log.Info("shutting down server...")
+	bgCancel()
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
 
@@ -95,3 +118,113 @@ func main() {
 	}
 	log.Info("server exited properly")
 }
+
+func runAbsenceReleaser(ctx context.Context, svc *service.Service, log *slog.Logger) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := svc.ReleaseStartedAbsences(ctx)
+			if err != nil {
+				log.Error("failed to release absent reviewers", "error", err)
+				continue
+			}
+			for _, a := range released {
+				log.Info("released reviews of absent user", "user_id", a.UserID, "absence_id", a.ID)
+			}
+		}
+	}
+}
+
+// runStaleReviewEscalator периодически напоминает о просроченных ревью и переназначает их.
+// Каждый экземпляр запускает проверку, но выполняет её только взявший advisory-блокировку
+func runStaleReviewEscalator(ctx context.Context, svc *service.Service, log *slog.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := svc.EscalateStaleReviews(ctx)
+			if err != nil {
+				log.Error("failed to escalate stale reviews", "error", err)
+				continue
+			}
+			if result.Skipped {
+				continue
+			}
+			for _, e := range result.Escalations {
+				if e.Error != "" {
+					log.Warn("failed to reassign stale review", "pull_request_id", e.PullRequestID, "user_id", e.ReviewerID, "error", e.Error)
+					continue
+				}
+				log.Info("reassigned stale review", "pull_request_id", e.PullRequestID, "from", e.ReviewerID, "to", e.NewReviewerID)
+			}
+		}
+	}
+}
+
+// newNotifier собирает каналы уведомлений из настроек; каналы без настроек не подключаются
+func newNotifier(cfg config.Config, log *slog.Logger) notify.Router {
+	router := notify.Router{domain.NotificationChannelLog: notify.NewLog(log)}
+	if cfg.NotificationFile != "" {
+		router[domain.NotificationChannelLog] = notify.NewFile(cfg.NotificationFile)
+	}
+	if cfg.SlackWebhookURL != "" {
+		router[domain.NotificationChannelSlack] = notify.NewSlack(cfg.SlackWebhookURL, &http.Client{Timeout: 10 * time.Second})
+	}
+	if cfg.SMTPAddr != "" {
+		router[domain.NotificationChannelEmail] = notify.NewSMTP(notify.SMTPConfig{
+			Addr:     cfg.SMTPAddr,
+			From:     cfg.SMTPFrom,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		})
+	}
+	return router
+}
+
+func runNotificationDelivery(ctx context.Context, svc *service.Service, log *slog.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := svc.DeliverNotifications(ctx)
+			if err != nil {
+				log.Error("failed to deliver notifications", "error", err)
+				continue
+			}
+			for _, msg := range result.Errors {
+				log.Error("failed to send notification", "error", msg)
+			}
+		}
+	}
+}
+
+// runDigestSender периодически отправляет ежедневные сводки тем, у кого наступил час сводки
+func runDigestSender(ctx context.Context, svc *service.Service, log *slog.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := svc.SendDigests(ctx)
+			if err != nil {
+				log.Error("failed to send digests", "error", err)
+				continue
+			}
+			for _, msg := range result.Errors {
+				log.Error("failed to send digest", "error", msg)
+			}
+		}
+	}
+}
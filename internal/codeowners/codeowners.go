@@ -0,0 +1,163 @@
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidSyntax = errors.New("invalid codeowners syntax")
+
+// Owner — владелец правила: пользователь (@user_id) или команда (@org/team_name)
+type Owner struct {
+	User string
+	Team string
+}
+
+func (o Owner) String() string {
+	if o.Team != "" {
+		return "@" + o.Team
+	}
+	return "@" + o.User
+}
+
+type Rule struct {
+	Line    int
+	Pattern string
+	Owners  []Owner
+
+	re *regexp.Regexp
+}
+
+func (r Rule) Matches(path string) bool {
+	return r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+type Ruleset struct {
+	rules []Rule
+}
+
+// Parse разбирает файл в синтаксисе GitHub CODEOWNERS.
+// Отрицания (!) не поддерживаются, владельцы указываются только через @
+func Parse(r io.Reader) (*Ruleset, error) {
+	rs := &Ruleset{}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		pattern := fields[0]
+		if strings.HasPrefix(pattern, "!") {
+			return nil, fmt.Errorf("%w: line %d: negation is not supported", ErrInvalidSyntax, lineNo)
+		}
+		pattern = strings.TrimPrefix(pattern, `\`)
+
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad pattern %q", ErrInvalidSyntax, lineNo, pattern)
+		}
+		rule := Rule{Line: lineNo, Pattern: pattern, Owners: make([]Owner, 0, len(fields)-1), re: re}
+		for _, f := range fields[1:] {
+			if strings.HasPrefix(f, "#") {
+				break
+			}
+			owner, err := parseOwner(f)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidSyntax, lineNo, err)
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		rs.rules = append(rs.rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading codeowners: %w", err)
+	}
+	return rs, nil
+}
+
+func (rs *Ruleset) Rules() []Rule {
+	return rs.rules
+}
+
+// Match возвращает правило для пути; как и в GitHub, побеждает последнее подходящее правило
+func (rs *Ruleset) Match(path string) (Rule, bool) {
+	for i := len(rs.rules) - 1; i >= 0; i-- {
+		if rs.rules[i].Matches(path) {
+			return rs.rules[i], true
+		}
+	}
+	return Rule{}, false
+}
+
+func parseOwner(s string) (Owner, error) {
+	name, ok := strings.CutPrefix(s, "@")
+	if !ok || name == "" {
+		return Owner{}, fmt.Errorf("owner %q must start with @", s)
+	}
+	if org, team, found := strings.Cut(name, "/"); found {
+		if org == "" || team == "" {
+			return Owner{}, fmt.Errorf("bad team owner %q", s)
+		}
+		return Owner{Team: team}, nil
+	}
+	return Owner{User: name}, nil
+}
+
+// compilePattern переводит шаблон в стиле gitignore в регулярное выражение.
+// Шаблон со слешем в начале или середине привязан к корню, иначе совпадает на любой глубине.
+// Совпадение с каталогом распространяется на всё его содержимое, кроме шаблонов вида dir/*
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return nil, errors.New("empty pattern")
+	}
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	segments := strings.Split(trimmed, "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		if seg == "**" {
+			if last {
+				b.WriteString(".*")
+			} else {
+				b.WriteString("(?:.*/)?")
+			}
+			continue
+		}
+		for _, r := range seg {
+			switch r {
+			case '*':
+				b.WriteString("[^/]*")
+			case '?':
+				b.WriteString("[^/]")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		if !last {
+			b.WriteString("/")
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*")
+	case segments[len(segments)-1] == "*" && len(segments) > 1, segments[len(segments)-1] == "**":
+	default:
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,90 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("OwnersAndComments", func(t *testing.T) {
+		data := "# default owners\n" +
+			"*       @global-owner\n" +
+			"\n" +
+			"/api/   @acme/backend @u1 # api\n"
+
+		rs, err := Parse(strings.NewReader(data))
+
+		require.NoError(t, err)
+		rules := rs.Rules()
+		require.Len(t, rules, 2)
+		assert.Equal(t, 2, rules[0].Line)
+		assert.Equal(t, []Owner{{User: "global-owner"}}, rules[0].Owners)
+		assert.Equal(t, []Owner{{Team: "backend"}, {User: "u1"}}, rules[1].Owners)
+	})
+
+	t.Run("LastMatchWins", func(t *testing.T) {
+		data := "*.go @gophers\n/internal/billing/ @acme/payments\n/internal/billing/legacy.go\n"
+		rs, err := Parse(strings.NewReader(data))
+		require.NoError(t, err)
+
+		rule, ok := rs.Match("internal/billing/invoice.go")
+		require.True(t, ok)
+		assert.Equal(t, "/internal/billing/", rule.Pattern)
+
+		rule, ok = rs.Match("/internal/billing/legacy.go")
+		require.True(t, ok)
+		assert.Empty(t, rule.Owners)
+
+		rule, ok = rs.Match("cmd/app/main.go")
+		require.True(t, ok)
+		assert.Equal(t, "*.go", rule.Pattern)
+
+		_, ok = rs.Match("README.md")
+		assert.False(t, ok)
+	})
+
+	t.Run("PatternSemantics", func(t *testing.T) {
+		cases := []struct {
+			pattern string
+			path    string
+			want    bool
+		}{
+			{"docs/*", "docs/intro.md", true},
+			{"docs/*", "docs/guide/setup.md", false},
+			{"apps/", "web/apps/index.js", true},
+			{"/apps/", "web/apps/index.js", false},
+			{"/build/logs", "build/logs/today.log", true},
+			{"**/logs", "deploy/logs/app.log", true},
+			{"src/**/test.go", "src/a/b/test.go", true},
+			{"src/**/test.go", "src/test.go", true},
+			{"docs/**", "docs/a/b.md", true},
+			{"*.js", "lib/vendor/x.js", true},
+			{"*.js", "lib/x.jsx", false},
+			{"file?.txt", "file1.txt", true},
+		}
+		for _, c := range cases {
+			rs, err := Parse(strings.NewReader(c.pattern + " @u\n"))
+			require.NoError(t, err)
+
+			_, ok := rs.Match(c.path)
+
+			assert.Equal(t, c.want, ok, "%s vs %s", c.pattern, c.path)
+		}
+	})
+
+	t.Run("InvalidOwner", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("*.go dev@example.com\n"))
+
+		require.ErrorIs(t, err, ErrInvalidSyntax)
+		assert.Contains(t, err.Error(), "line 1")
+	})
+
+	t.Run("NegationNotSupported", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("!*.md @docs\n"))
+
+		require.ErrorIs(t, err, ErrInvalidSyntax)
+	})
+}
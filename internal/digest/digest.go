@@ -0,0 +1,54 @@
+package digest
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+
+	"reviewer/internal/domain"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var funcs = map[string]any{
+	"age":        age,
+	"formatTime": func(t time.Time) string { return t.Format("2006-01-02 15:04 MST") },
+	"join":       strings.Join,
+}
+
+var (
+	markdownTemplate = template.Must(template.New("digest.md.tmpl").Funcs(funcs).ParseFS(templates, "templates/digest.md.tmpl"))
+	htmlTemplate     = htmltemplate.Must(htmltemplate.New("digest.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/digest.html.tmpl"))
+)
+
+// Markdown рендерит сводку для Slack, журнала и текстовой части письма
+func Markdown(d domain.Digest) (string, error) {
+	var b strings.Builder
+	if err := markdownTemplate.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("rendering markdown digest: %w", err)
+	}
+	return b.String(), nil
+}
+
+// HTML рендерит сводку для писем; пользовательские строки экранируются
+func HTML(d domain.Digest) (string, error) {
+	var b strings.Builder
+	if err := htmlTemplate.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("rendering html digest: %w", err)
+	}
+	return b.String(), nil
+}
+
+func age(hours int) string {
+	if hours < 24 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	if hours%24 == 0 {
+		return fmt.Sprintf("%dd", hours/24)
+	}
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
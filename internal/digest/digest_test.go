@@ -0,0 +1,62 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+)
+
+func TestRender(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	d := domain.Digest{
+		UserID:      "u1",
+		GeneratedAt: now,
+		Since:       now.Add(-24 * time.Hour),
+		OpenReviews: []domain.DigestReview{
+			{PullRequestShort: domain.PullRequestShort{ID: "pr-1", Title: "Fix <script>", AuthorID: "u2"}, AgeHours: 27},
+		},
+		AwaitingReview: []domain.AwaitingPR{
+			{PullRequestShort: domain.PullRequestShort{ID: "pr-2", Title: "Add cache"}, NeededReviewers: 2,
+				Reviewers: []string{"u3"}, AwaitingReviewers: []string{"u3"}},
+		},
+		RecentlyMerged: []domain.MergedPR{},
+		Notifications:  []domain.Notification{{Text: "You were assigned to review pr-1", CreatedAt: now}},
+	}
+
+	t.Run("Markdown", func(t *testing.T) {
+		out, err := Markdown(d)
+
+		require.NoError(t, err)
+		assert.Contains(t, out, "# Review digest for u1")
+		assert.Contains(t, out, "- **pr-1** Fix <script> by u2, waiting 1d 3h")
+		assert.Contains(t, out, "- **pr-2** Add cache: 1 of 2 reviewers assigned, waiting for u3")
+		assert.Contains(t, out, "## Recently merged (0)\n\nNone.")
+		assert.Contains(t, out, "- 2025-03-10 09:00 UTC You were assigned to review pr-1")
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		out, err := HTML(d)
+
+		require.NoError(t, err)
+		assert.Contains(t, out, "<li><b>pr-1</b> Fix &lt;script&gt; by u2, waiting 1d 3h</li>")
+		assert.NotContains(t, out, "<script>")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		out, err := Markdown(domain.Digest{UserID: "u1"})
+
+		require.NoError(t, err)
+		assert.Contains(t, out, "Nothing to review.")
+		assert.NotContains(t, out, "## Notifications")
+	})
+}
+
+func TestAge(t *testing.T) {
+	assert.Equal(t, "5h", age(5))
+	assert.Equal(t, "2d", age(48))
+	assert.Equal(t, "1d 3h", age(27))
+}
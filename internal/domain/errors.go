@@ -9,4 +9,11 @@ var (
 	ErrReviewerExist = errors.New("user is already a reviewer")
 	ErrNotAssigned   = errors.New("user is not assigned as reviewer")
 	ErrPRMerged      = errors.New("pull request is already merged")
+	ErrAlreadyUndone = errors.New("operation is already undone")
+	ErrCapacityFull  = errors.New("all candidates reached their open review limit")
+	ErrUnknownOwner  = errors.New("unknown code owner")
+	ErrManagerCycle  = errors.New("manager assignment creates a cycle")
+	ErrForbidden     = errors.New("only the author or the team lead can change reviewers")
+	ErrNotEligible   = errors.New("user cannot review this pull request")
+	ErrNotTeamMember = errors.New("user is not a member of the team")
 )
@@ -3,8 +3,49 @@ package domain
 import "time"
 
 type Team struct {
-	Name    string       `json:"team_name"`
-	Members []TeamMember `json:"members"`
+	Name                  string             `json:"team_name"`
+	Members               []TeamMember       `json:"members"`
+	DefaultMaxOpenReviews *int               `json:"default_max_open_reviews,omitempty"`
+	AssignmentStrategy    AssignmentStrategy `json:"assignment_strategy"`
+	RotationWindowDays    int                `json:"rotation_window_days"`
+	ReviewRules           TeamReviewRules    `json:"review_rules"`
+	// LeadID — руководитель команды; вместе с автором может вручную менять ревьюеров PR команды
+	LeadID    *string       `json:"lead_id"`
+	ReviewSLA TeamReviewSLA `json:"review_sla"`
+}
+
+// TeamReviewSLA — сроки, в которые ревьюер должен отреагировать на PR команды.
+// Через ReminderHours после назначения ему отправляется напоминание, через EscalationHours
+// ревью переназначается; nil отключает соответствующий шаг
+type TeamReviewSLA struct {
+	ReminderHours   *int `json:"reminder_hours"`
+	EscalationHours *int `json:"escalation_hours"`
+}
+
+// TeamReviewRules — правила состава ревьюеров на PR команды
+type TeamReviewRules struct {
+	// RequireSenior требует хотя бы одного senior-ревьюера на каждом PR
+	RequireSenior bool `json:"require_senior"`
+	// JuniorNeedsSenior требует senior-ревьюера на PR junior-авторов
+	JuniorNeedsSenior bool `json:"junior_needs_senior"`
+	// ShadowReviewer добавляет на PR junior-стажёра, не влияющего на merge
+	ShadowReviewer bool `json:"shadow_reviewer"`
+}
+
+// AssignmentStrategy определяет, как выбираются ревьюеры из кандидатов команды
+type AssignmentStrategy string
+
+const (
+	AssignmentStrategyRandom AssignmentStrategy = "RANDOM"
+	// AssignmentStrategyRotation штрафует недавние пары автор→ревьюер, чтобы ревью распределялись по команде
+	AssignmentStrategyRotation AssignmentStrategy = "ROTATION"
+	// AssignmentStrategyRuleSet записывается в объяснение назначения, сделанного набором правил команды;
+	// задать её через /team/setStrategy нельзя
+	AssignmentStrategyRuleSet AssignmentStrategy = "RULE_SET"
+)
+
+func (s AssignmentStrategy) Valid() bool {
+	return s == AssignmentStrategyRandom || s == AssignmentStrategyRotation
 }
 
 type TeamMember struct {
@@ -14,10 +55,54 @@ type TeamMember struct {
 }
 
 type User struct {
-	ID       string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	ID             string    `json:"user_id"`
+	Username       string    `json:"username"`
+	TeamName       string    `json:"team_name"`
+	IsActive       bool      `json:"is_active"`
+	MaxOpenReviews *int      `json:"max_open_reviews,omitempty"`
+	ExpertiseTags  []string  `json:"expertise_tags,omitempty"`
+	ManagerID      *string   `json:"manager_id,omitempty"`
+	Seniority      Seniority `json:"seniority,omitempty"`
+}
+
+type Seniority string
+
+const (
+	SeniorityJunior Seniority = "JUNIOR"
+	SeniorityMid    Seniority = "MID"
+	SenioritySenior Seniority = "SENIOR"
+)
+
+func (s Seniority) Valid() bool {
+	return s == SeniorityJunior || s == SeniorityMid || s == SenioritySenior
+}
+
+// ReviewExclusion запрещает пользователю ReviewerID ревьюить PR автора AuthorID
+type ReviewExclusion struct {
+	ID         int64     `json:"exclusion_id"`
+	ReviewerID string    `json:"reviewer_id"`
+	AuthorID   string    `json:"author_id"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ReviewerLoad struct {
+	UserID         string `json:"user_id"`
+	OpenReviews    int    `json:"open_reviews"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+func (l ReviewerLoad) HasCapacity() bool {
+	return l.MaxOpenReviews == nil || l.OpenReviews < *l.MaxOpenReviews
+}
+
+type Unavailability struct {
+	ID         int64      `json:"id"`
+	UserID     string     `json:"user_id"`
+	From       time.Time  `json:"from"`
+	To         time.Time  `json:"to"`
+	Reason     string     `json:"reason"`
+	ReleasedAt *time.Time `json:"released_at"`
 }
 
 type PRStatus string
@@ -27,15 +112,158 @@ const (
 	PRStatusMerged PRStatus = "MERGED"
 )
 
+const DefaultNeededReviewers = 2
+
 type PullRequest struct {
-	ID        string     `json:"pull_request_id"`
-	Title     string     `json:"pull_request_name"`
-	AuthorID  string     `json:"author_id"`
-	Status    PRStatus   `json:"status"`
-	Reviewers []string   `json:"assigned_reviewers"`
-	CreatedAt time.Time  `json:"createdAt"`
-	MergedAt  *time.Time `json:"mergedAt"`
-	TeamName  string     `json:"-"`
+	ID                string     `json:"pull_request_id"`
+	Title             string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            PRStatus   `json:"status"`
+	Reviewers         []string   `json:"assigned_reviewers"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	MergedAt          *time.Time `json:"mergedAt"`
+	Repository        string     `json:"repository,omitempty"`
+	Labels            []string   `json:"labels"`
+	ChangedPaths      []string   `json:"changed_paths"`
+	ExcludedReviewers []string   `json:"excluded_reviewers"`
+	ShadowReviewers   []string   `json:"shadow_reviewers"`
+	TeamName          string     `json:"-"`
+	NeededReviewers   int        `json:"-"`
+
+	CapacityExhausted bool                  `json:"capacity_exhausted,omitempty"`
+	Violations        []ConstraintViolation `json:"constraint_violations,omitempty"`
+	Matches           []ReviewerMatch       `json:"reviewer_matches,omitempty"`
+	RuleTrace         *RuleTrace            `json:"rule_trace,omitempty"`
+}
+
+type ReviewRule string
+
+const (
+	ReviewRuleSeniorReviewer    ReviewRule = "SENIOR_REVIEWER"
+	ReviewRuleJuniorNeedsSenior ReviewRule = "JUNIOR_NEEDS_SENIOR"
+	ReviewRuleShadowReviewer    ReviewRule = "SHADOW_REVIEWER"
+	// ReviewRuleRuleSet — требование из набора правил команды; имя требования в Message
+	ReviewRuleRuleSet ReviewRule = "RULE_SET_REQUIREMENT"
+)
+
+// ConstraintViolation сообщает, какое правило команды не удалось выполнить при подборе ревьюеров
+type ConstraintViolation struct {
+	Rule    ReviewRule `json:"rule"`
+	Message string     `json:"message"`
+}
+
+// ExclusionReason объясняет, почему участник команды не может быть ревьюером PR
+type ExclusionReason string
+
+const (
+	ExclusionAuthor ExclusionReason = "AUTHOR"
+	// ExclusionConflict — руководитель автора или исключён правилом для автора
+	ExclusionConflict ExclusionReason = "CONFLICT_OF_INTEREST"
+	// ExclusionRequested — исключён в самом PR
+	ExclusionRequested  ExclusionReason = "EXCLUDED_BY_REQUEST"
+	ExclusionAtCapacity ExclusionReason = "AT_CAPACITY"
+	// ExclusionUnavailable — неактивен или отсутствует
+	ExclusionUnavailable ExclusionReason = "UNAVAILABLE"
+)
+
+type CandidateExclusion struct {
+	UserID string          `json:"user_id"`
+	Reason ExclusionReason `json:"reason"`
+}
+
+// AssignmentPreview — результат пробного подбора ревьюеров для PR, который ещё не создан
+type AssignmentPreview struct {
+	TeamName        string             `json:"team_name"`
+	Strategy        AssignmentStrategy `json:"strategy"`
+	NeededReviewers int                `json:"needed_reviewers"`
+	// Candidates — доступные участники команд, отвечающих за PR, с их нагрузкой
+	Candidates []ReviewerLoad       `json:"candidates"`
+	Exclusions []CandidateExclusion `json:"exclusions"`
+	Reviewers  []ReviewerMatch      `json:"reviewers"`
+	// Probabilities — вероятность каждого кандидата попасть в ревьюеры с учётом мест
+	// владельцев кода и senior-ревьюера. Для набора правил не заполняется
+	Probabilities     map[string]float64    `json:"selection_probabilities,omitempty"`
+	CapacityExhausted bool                  `json:"capacity_exhausted"`
+	Violations        []ConstraintViolation `json:"constraint_violations,omitempty"`
+	RuleTrace         *RuleTrace            `json:"rule_trace,omitempty"`
+}
+
+type MatchReason string
+
+const (
+	MatchReasonCodeOwner MatchReason = "CODEOWNER"
+	MatchReasonExpertise MatchReason = "EXPERTISE_MATCH"
+	MatchReasonSeniority MatchReason = "SENIORITY_RULE"
+	MatchReasonRuleSet   MatchReason = "RULE_SET"
+	MatchReasonRandom    MatchReason = "RANDOM"
+	// MatchReasonManual — ревьюер добавлен вручную автором или руководителем команды
+	MatchReasonManual MatchReason = "MANUAL"
+)
+
+// ReviewerMatch объясняет, почему ревьюер был выбран для PR.
+// Для назначений, сделанных до появления объяснений, Reason пуст
+type ReviewerMatch struct {
+	UserID      string         `json:"user_id"`
+	Reason      MatchReason    `json:"reason,omitempty"`
+	MatchedTags []string       `json:"matched_tags"`
+	Rule        *OwnershipRule `json:"matched_rule,omitempty"`
+	// Explanation пуст для назначений, сделанных до появления объяснений
+	Explanation *AssignmentExplanation `json:"explanation,omitempty"`
+}
+
+// AssignmentExplanation фиксирует условия выбора ревьюера: по стратегии, размеру пула, нагрузке
+// кандидата и зерну генератора выбор можно проверить и воспроизвести
+type AssignmentExplanation struct {
+	Strategy AssignmentStrategy `json:"strategy"`
+	// PoolSize — число кандидатов, из которых выбирался ревьюер, после исключений и проверки лимитов
+	PoolSize int `json:"pool_size"`
+	// OpenReviews — число открытых ревью кандидата в момент выбора
+	OpenReviews int   `json:"open_reviews"`
+	Seed        int64 `json:"seed"`
+}
+
+// OwnershipRule — правило CODEOWNERS команды, по которому выбран ревьюер
+type OwnershipRule struct {
+	TeamName string `json:"team_name"`
+	Line     int    `json:"line"`
+	Pattern  string `json:"pattern"`
+	Path     string `json:"path"`
+}
+
+// Repository — репозиторий кода; ревьюеры PR подбираются из команд-владельцев
+type Repository struct {
+	Name       string    `json:"repository"`
+	OwnerTeams []string  `json:"owner_teams"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RuleSetVersion — версия декларативного набора правил назначения команды (YAML или JSON)
+type RuleSetVersion struct {
+	TeamName  string    `json:"team_name"`
+	Version   int       `json:"version"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RuleTrace объясняет, как набор правил команды выбирал ревьюеров PR при последнем подборе
+type RuleTrace struct {
+	TeamName    string          `json:"team_name"`
+	Version     int             `json:"version"`
+	Steps       []RuleTraceStep `json:"steps"`
+	EvaluatedAt time.Time       `json:"evaluated_at"`
+}
+
+type RuleTraceStep struct {
+	Kind   string `json:"kind"`
+	Rule   string `json:"rule,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+	Detail string `json:"detail"`
+}
+
+type CodeOwnersFile struct {
+	TeamName  string    `json:"team_name"`
+	Content   string    `json:"content"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type PullRequestShort struct {
@@ -43,14 +271,443 @@ type PullRequestShort struct {
 	Title    string   `json:"pull_request_name"`
 	AuthorID string   `json:"author_id"`
 	Status   PRStatus `json:"status"`
+	// AssignedAt — время назначения ревьюера; заполняется только в списках ревью пользователя
+	AssignedAt *time.Time `json:"assigned_at,omitempty"`
+}
+
+type UnderstaffedPR struct {
+	PullRequestShort
+	TeamName        string   `json:"team_name"`
+	NeededReviewers int      `json:"needed_reviewers"`
+	Reviewers       []string `json:"assigned_reviewers"`
+}
+
+type PRBackfill struct {
+	PullRequestID     string   `json:"pull_request_id"`
+	Added             []string `json:"added_reviewers"`
+	Understaffed      bool     `json:"understaffed"`
+	CapacityExhausted bool     `json:"capacity_exhausted,omitempty"`
+
+	Violations []ConstraintViolation `json:"constraint_violations,omitempty"`
+}
+
+type StatsGroupBy string
+
+const (
+	StatsGroupByUser StatsGroupBy = "user"
+	StatsGroupByTeam StatsGroupBy = "team"
+	StatsGroupByWeek StatsGroupBy = "week"
+)
+
+func (g StatsGroupBy) Valid() bool {
+	return g == StatsGroupByUser || g == StatsGroupByTeam || g == StatsGroupByWeek
+}
+
+// AssignmentStatsFilter отбирает назначения из истории; пустые поля выборку не ограничивают
+type AssignmentStatsFilter struct {
+	// From и To задают полуинтервал [From, To) времени назначения
+	From *time.Time
+	To   *time.Time
+	// TeamName — команда ревьюера
+	TeamName string
+	// Status — текущий статус PR
+	Status  PRStatus
+	GroupBy StatsGroupBy
+}
+
+// AssignmentStats — число назначений в группе; заполнен только ключ выбранной группировки
+type AssignmentStats struct {
+	UserID          string     `json:"user_id,omitempty"`
+	TeamName        string     `json:"team_name,omitempty"`
+	WeekStart       *time.Time `json:"week_start,omitempty"`
+	AssignmentCount int64      `json:"assignment_count"`
+	// RemovedCount — назначения, ревьюер которых больше не назначен на PR:
+	// его переназначили, он отказался или был снят вручную
+	RemovedCount int64 `json:"removed_count"`
+}
+
+// PairAssignment — одно назначение ревьюера на PR автора из истории назначений
+type PairAssignment struct {
+	ReviewerID string
+	AssignedAt time.Time
+}
+
+type AssignmentAction string
+
+const (
+	AssignmentActionAssigned AssignmentAction = "ASSIGNED"
+	// AssignmentActionRemoved — ревьюер снят с PR вручную
+	AssignmentActionRemoved AssignmentAction = "REMOVED"
+	// AssignmentActionDeclined — ревьюер сам отказался от ревью
+	AssignmentActionDeclined AssignmentAction = "DECLINED"
+)
+
+// DeclineReason — причина, по которой ревьюер отказался от ревью
+type DeclineReason string
+
+const (
+	DeclineReasonBusy         DeclineReason = "BUSY"
+	DeclineReasonLacksContext DeclineReason = "LACKS_CONTEXT"
+	DeclineReasonConflict     DeclineReason = "CONFLICT"
+)
+
+func (r DeclineReason) Valid() bool {
+	return r == DeclineReasonBusy || r == DeclineReasonLacksContext || r == DeclineReasonConflict
+}
+
+// AssignmentRecord — запись истории назначений ревьюеров на PR
+type AssignmentRecord struct {
+	ReviewerID string           `json:"reviewer_id"`
+	AuthorID   string           `json:"author_id"`
+	Action     AssignmentAction `json:"action"`
+	// ActorID — кто изменил состав ревьюеров вручную; пуст для автоматических назначений
+	ActorID       string                 `json:"actor_id,omitempty"`
+	DeclineReason DeclineReason          `json:"decline_reason,omitempty"`
+	Reason        MatchReason            `json:"reason,omitempty"`
+	Explanation   *AssignmentExplanation `json:"explanation,omitempty"`
+	AssignedAt    time.Time              `json:"assigned_at"`
+}
+
+// DeclineStats — отказы от ревью пользователя или команды. DeclineRate — доля отказов
+// среди назначений; ByReason — число отказов по причинам
+type DeclineStats struct {
+	UserID      string                  `json:"user_id,omitempty"`
+	TeamName    string                  `json:"team_name"`
+	Assignments int64                   `json:"assignments"`
+	Declines    int64                   `json:"declines"`
+	DeclineRate float64                 `json:"decline_rate"`
+	ByReason    map[DeclineReason]int64 `json:"by_reason"`
+}
+
+// LatencyFilter отбирает PR, созданные в полуинтервале [From, To); пустые поля выборку не ограничивают
+type LatencyFilter struct {
+	From     *time.Time
+	To       *time.Time
+	TeamName string
+}
+
+// LatencyPercentiles — перцентили длительности в часах по Count замерам; без замеров перцентили пусты
+type LatencyPercentiles struct {
+	Count int64    `json:"count"`
+	P50   *float64 `json:"p50_hours"`
+	P90   *float64 `json:"p90_hours"`
+	P99   *float64 `json:"p99_hours"`
+}
+
+// LatencyStats — скорость ревью команды или ревьюера. Для команды время до первого ревью
+// и до одобрения отсчитывается от создания PR, для ревьюера — от его назначения.
+// Время до слияния всегда отсчитывается от создания PR
+type LatencyStats struct {
+	TeamName          string             `json:"team_name,omitempty"`
+	UserID            string             `json:"user_id,omitempty"`
+	TimeToFirstReview LatencyPercentiles `json:"time_to_first_review"`
+	TimeToApproval    LatencyPercentiles `json:"time_to_approval"`
+	TimeToMerge       LatencyPercentiles `json:"time_to_merge"`
 }
 
-type UserAssignmentStats struct {
-	UserID          string `json:"user_id"`
-	AssignmentCount int64  `json:"assignment_count"`
+type PairStats struct {
+	AuthorID        string    `json:"author_id"`
+	ReviewerID      string    `json:"reviewer_id"`
+	AssignmentCount int64     `json:"assignment_count"`
+	LastAssignedAt  time.Time `json:"last_assigned_at"`
 }
 
 type DeactivationResult struct {
+	OperationID      int64              `json:"operation_id,omitempty"`
 	DeactivatedUsers []User             `json:"deactivated_users"`
 	AffectedPRs      []PullRequestShort `json:"affected_prs"`
+	Reassignments    []PRReassignment   `json:"reassignments,omitempty"`
+}
+
+type PRReassignment struct {
+	PullRequestID     string   `json:"pull_request_id"`
+	Removed           []string `json:"removed_reviewers"`
+	Replacements      []string `json:"replacements"`
+	Understaffed      bool     `json:"understaffed"`
+	CapacityExhausted bool     `json:"capacity_exhausted,omitempty"`
+
+	Violations []ConstraintViolation `json:"constraint_violations,omitempty"`
+}
+
+type HandoffStatus string
+
+const (
+	HandoffReassigned HandoffStatus = "REASSIGNED"
+	// HandoffFailed — замены не нашлось, ревью осталось за пользователем
+	HandoffFailed HandoffStatus = "FAILED"
+)
+
+// HandoffOutcome — результат передачи одного открытого ревью
+type HandoffOutcome struct {
+	PullRequestID string        `json:"pull_request_id"`
+	Status        HandoffStatus `json:"status"`
+	NewReviewerID string        `json:"new_reviewer_id,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+type HandoffResult struct {
+	UserID     string           `json:"user_id"`
+	Reassigned int              `json:"reassigned"`
+	Failed     int              `json:"failed"`
+	Outcomes   []HandoffOutcome `json:"outcomes"`
+}
+
+// RebalanceMove — перенос одного открытого ревью от перегруженного участника к менее загруженному
+type RebalanceMove struct {
+	PullRequestID string `json:"pull_request_id"`
+	FromUserID    string `json:"from_user_id"`
+	ToUserID      string `json:"to_user_id"`
+}
+
+type RebalanceResult struct {
+	TeamName  string `json:"team_name"`
+	DryRun    bool   `json:"dry_run"`
+	Tolerance int    `json:"tolerance"`
+	// SpreadBefore и SpreadAfter — разница между наибольшей и наименьшей нагрузкой участников
+	SpreadBefore int             `json:"spread_before"`
+	SpreadAfter  int             `json:"spread_after"`
+	Moves        []RebalanceMove `json:"moves"`
+	Loads        []ReviewerLoad  `json:"loads"`
+}
+
+// ReviewDecision — реакция ревьюера на PR; любая реакция снимает ревью из просроченных
+type ReviewDecision string
+
+const (
+	ReviewDecisionApproved         ReviewDecision = "APPROVED"
+	ReviewDecisionChangesRequested ReviewDecision = "CHANGES_REQUESTED"
+	ReviewDecisionCommented        ReviewDecision = "COMMENTED"
+)
+
+func (d ReviewDecision) Valid() bool {
+	return d == ReviewDecisionApproved || d == ReviewDecisionChangesRequested || d == ReviewDecisionCommented
+}
+
+type Review struct {
+	ID            int64          `json:"review_id"`
+	PullRequestID string         `json:"pull_request_id"`
+	ReviewerID    string         `json:"reviewer_id"`
+	Decision      ReviewDecision `json:"decision"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// StaleReview — назначение на открытый PR, по которому ревьюер не отреагировал
+// с момента AssignedAt и уже вышел за срок напоминания или переназначения команды.
+// EscalationFailedAt — время последней неудачной попытки переназначения
+type StaleReview struct {
+	PullRequestID      string        `json:"pull_request_id"`
+	ReviewerID         string        `json:"reviewer_id"`
+	TeamName           string        `json:"team_name"`
+	AssignedAt         time.Time     `json:"assigned_at"`
+	RemindedAt         *time.Time    `json:"reminded_at"`
+	EscalationFailedAt *time.Time    `json:"escalation_failed_at"`
+	SLA                TeamReviewSLA `json:"review_sla"`
+}
+
+type Escalation struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+	// Error — причина, по которой замену найти не удалось; переназначение повторится при следующей проверке
+	Error string `json:"error,omitempty"`
+}
+
+type EscalationResult struct {
+	// Skipped — проверку в это же время выполняет другой экземпляр сервиса
+	Skipped     bool               `json:"skipped"`
+	Reminded    []ReviewAssignment `json:"reminded"`
+	Escalations []Escalation       `json:"escalations"`
+}
+
+type NotificationKind string
+
+const (
+	NotificationReviewAssigned   NotificationKind = "REVIEW_ASSIGNED"
+	NotificationReviewReassigned NotificationKind = "REVIEW_REASSIGNED"
+	NotificationReviewDeclined   NotificationKind = "REVIEW_DECLINED"
+	// NotificationReviewReminder — ревьюер не отреагировал на PR в срок команды
+	NotificationReviewReminder NotificationKind = "REVIEW_REMINDER"
+	// NotificationReviewEscalated — ревью снято с ревьюера, не отреагировавшего в срок
+	NotificationReviewEscalated NotificationKind = "REVIEW_ESCALATED"
+	// NotificationDigest — ежедневная сводка пользователя
+	NotificationDigest NotificationKind = "DIGEST"
+)
+
+// Notification — событие для пользователя UserID; доставляется фоновой отправкой
+// по его настройкам уведомлений
+type Notification struct {
+	ID            int64            `json:"notification_id"`
+	UserID        string           `json:"user_id"`
+	Kind          NotificationKind `json:"kind"`
+	PullRequestID string           `json:"pull_request_id,omitempty"`
+	Text          string           `json:"text"`
+	CreatedAt     time.Time        `json:"created_at"`
+	// HTML — необязательная HTML-версия Text для каналов, которые её поддерживают
+	HTML string `json:"-"`
+}
+
+type NotificationChannel string
+
+const (
+	NotificationChannelSlack NotificationChannel = "SLACK"
+	NotificationChannelEmail NotificationChannel = "EMAIL"
+	NotificationChannelLog   NotificationChannel = "LOG"
+	// NotificationChannelNone отключает уведомления пользователя
+	NotificationChannelNone NotificationChannel = "NONE"
+)
+
+func (c NotificationChannel) Valid() bool {
+	return c == NotificationChannelSlack || c == NotificationChannelEmail || c == NotificationChannelLog || c == NotificationChannelNone
+}
+
+type NotificationDelivery string
+
+const (
+	NotificationDeliveryImmediate NotificationDelivery = "IMMEDIATE"
+	// NotificationDeliveryDigest копит уведомления до ежедневной сводки
+	NotificationDeliveryDigest NotificationDelivery = "DIGEST"
+)
+
+func (d NotificationDelivery) Valid() bool {
+	return d == NotificationDeliveryImmediate || d == NotificationDeliveryDigest
+}
+
+// QuietHours — часы [Start, End) в часовом поясе пользователя, когда уведомления откладываются.
+// Start больше End означает интервал через полночь
+type QuietHours struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+func (q QuietHours) Valid() bool {
+	return q.Start >= 0 && q.Start < 24 && q.End >= 0 && q.End < 24 && q.Start != q.End
+}
+
+func (q QuietHours) Contains(hour int) bool {
+	if q.Start < q.End {
+		return hour >= q.Start && hour < q.End
+	}
+	return hour >= q.Start || hour < q.End
+}
+
+type NotificationPreferences struct {
+	UserID  string              `json:"user_id"`
+	Channel NotificationChannel `json:"channel"`
+	// Address — email для EMAIL или идентификатор участника Slack для упоминания в SLACK
+	Address    string               `json:"address,omitempty"`
+	Delivery   NotificationDelivery `json:"delivery"`
+	QuietHours *QuietHours          `json:"quiet_hours"`
+	Timezone   string               `json:"timezone"`
+}
+
+// DefaultNotificationPreferences — настройки пользователя, который их не менял
+func DefaultNotificationPreferences(userID string) NotificationPreferences {
+	return NotificationPreferences{
+		UserID:   userID,
+		Channel:  NotificationChannelLog,
+		Delivery: NotificationDeliveryImmediate,
+		Timezone: "UTC",
+	}
+}
+
+// Location возвращает часовой пояс пользователя; неизвестный пояс считается UTC
+func (p NotificationPreferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func (p NotificationPreferences) InQuietHours(t time.Time) bool {
+	if p.QuietHours == nil {
+		return false
+	}
+	return p.QuietHours.Contains(t.In(p.Location()).Hour())
+}
+
+// PendingNotification — неотправленное уведомление вместе с текущими настройками получателя
+type PendingNotification struct {
+	Notification
+	Preferences NotificationPreferences
+}
+
+type DeliveryResult struct {
+	Sent int `json:"sent"`
+	// Deferred — уведомления, отложенные до конца тихих часов получателя
+	Deferred int      `json:"deferred"`
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors"`
+}
+
+// DigestReview — открытое ревью пользователя; AgeHours отсчитывается от назначения
+type DigestReview struct {
+	PullRequestShort
+	AgeHours int `json:"age_hours"`
+}
+
+// AwaitingPR — открытый PR пользователя, который ещё ждёт ревьюеров: назначены не все
+// или не все назначенные одобрили его
+type AwaitingPR struct {
+	PullRequestShort
+	CreatedAt         time.Time `json:"created_at"`
+	NeededReviewers   int       `json:"needed_reviewers"`
+	Reviewers         []string  `json:"assigned_reviewers"`
+	AwaitingReviewers []string  `json:"awaiting_reviewers"`
+}
+
+type MergedPR struct {
+	PullRequestShort
+	MergedAt time.Time `json:"merged_at"`
+}
+
+// Digest — сводка пользователя за период с Since; время указано в его часовом поясе
+type Digest struct {
+	UserID         string         `json:"user_id"`
+	GeneratedAt    time.Time      `json:"generated_at"`
+	Since          time.Time      `json:"since"`
+	OpenReviews    []DigestReview `json:"open_reviews"`
+	AwaitingReview []AwaitingPR   `json:"awaiting_review"`
+	RecentlyMerged []MergedPR     `json:"recently_merged"`
+	// Notifications — уведомления, накопленные в режиме сводки
+	Notifications []Notification `json:"notifications"`
+}
+
+func (d Digest) Empty() bool {
+	return len(d.OpenReviews) == 0 && len(d.AwaitingReview) == 0 && len(d.RecentlyMerged) == 0 && len(d.Notifications) == 0
+}
+
+// DigestRecipient — пользователь в режиме сводки и время отправки его последней сводки
+type DigestRecipient struct {
+	Preferences NotificationPreferences
+	LastSentAt  *time.Time
+}
+
+type ReviewAssignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+type OperationKind string
+
+const (
+	OperationTeamDeactivation OperationKind = "TEAM_DEACTIVATION"
+)
+
+type Operation struct {
+	ID             int64              `json:"operation_id"`
+	Kind           OperationKind      `json:"kind"`
+	TeamName       string             `json:"team_name"`
+	UserIDs        []string           `json:"user_ids"`
+	RemovedReviews []ReviewAssignment `json:"removed_reviews"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UndoneAt       *time.Time         `json:"undone_at"`
+}
+
+type UndoResult struct {
+	OperationID      int64              `json:"operation_id"`
+	ReactivatedUsers []User             `json:"reactivated_users"`
+	RestoredReviews  []ReviewAssignment `json:"restored_reviews"`
+	SkippedReviews   []ReviewAssignment `json:"skipped_reviews"`
+	Backfilled       []PRBackfill       `json:"backfilled"`
 }
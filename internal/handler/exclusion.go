@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"reviewer/internal/domain"
+)
+
+func (h *Handler) CreateReviewExclusion(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ReviewerID string `json:"reviewer_id"`
+		AuthorID   string `json:"author_id"`
+		Reason     string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.ReviewerID == "" || req.AuthorID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "reviewer_id and author_id are required")
+		return
+	}
+	if req.ReviewerID == req.AuthorID {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "reviewer_id and author_id must differ")
+		return
+	}
+
+	e, err := h.svc.CreateReviewExclusion(r.Context(), domain.ReviewExclusion{
+		ReviewerID: req.ReviewerID,
+		AuthorID:   req.AuthorID,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"exclusion": e})
+}
+
+func (h *Handler) DeleteReviewExclusion(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID int64 `json:"exclusion_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.ID <= 0 {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "exclusion_id is required")
+		return
+	}
+
+	if err := h.svc.DeleteReviewExclusion(r.Context(), req.ID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"exclusion_id": req.ID})
+}
+
+func (h *Handler) ListReviewExclusions(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	exclusions, err := h.svc.ListReviewExclusions(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"user_id": userID, "exclusions": exclusions})
+}
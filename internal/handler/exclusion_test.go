@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Exclusion(t *testing.T) {
+	r, _, teardown := setupIntegration(t)
+	defer teardown()
+
+	team := `{"team_name": "excl-api", "members": [
+		{"user_id": "xa", "username": "A", "is_active": true},
+		{"user_id": "xr", "username": "R", "is_active": true}
+	]}`
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(team)))
+
+	t.Run("AddListRemove", func(t *testing.T) {
+		addBody := `{"reviewer_id": "xr", "author_id": "xa", "reason": "co-author"}`
+		addW := httptest.NewRecorder()
+		r.ServeHTTP(addW, httptest.NewRequest(http.MethodPost, "/exclusions/add", bytes.NewBufferString(addBody)))
+		require.Equal(t, http.StatusCreated, addW.Code)
+		var added map[string]map[string]any
+		require.NoError(t, json.Unmarshal(addW.Body.Bytes(), &added))
+		id := added["exclusion"]["exclusion_id"]
+
+		listW := httptest.NewRecorder()
+		r.ServeHTTP(listW, httptest.NewRequest(http.MethodGet, "/exclusions/list?user_id=xa", http.NoBody))
+		var list map[string]any
+		require.NoError(t, json.Unmarshal(listW.Body.Bytes(), &list))
+		assert.Len(t, list["exclusions"], 1)
+
+		removeBody, _ := json.Marshal(map[string]any{"exclusion_id": id})
+		removeW := httptest.NewRecorder()
+		r.ServeHTTP(removeW, httptest.NewRequest(http.MethodPost, "/exclusions/remove", bytes.NewBuffer(removeBody)))
+
+		assert.Equal(t, http.StatusOK, removeW.Code)
+	})
+
+	t.Run("Add_SelfPair", func(t *testing.T) {
+		body := `{"reviewer_id": "xa", "author_id": "xa"}`
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/exclusions/add", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("SetUserManager", func(t *testing.T) {
+		body := `{"user_id": "xa", "manager_id": "xr"}`
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/setManager", bytes.NewBufferString(body)))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "xr", resp["user"]["manager_id"])
+	})
+}
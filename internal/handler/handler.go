@@ -25,13 +25,54 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Post("/team/add", h.CreateTeam)
 	r.Get("/team/get", h.GetTeam)
 	r.Post("/team/deactivate", h.DeactivateTeam)
+	r.Post("/team/reactivate", h.ReactivateTeam)
+	r.Post("/team/rebalance", h.RebalanceTeam)
+	r.Post("/team/setCapacity", h.SetTeamCapacity)
+	r.Post("/team/setLead", h.SetTeamLead)
+	r.Post("/team/setStrategy", h.SetTeamStrategy)
+	r.Post("/team/setReviewRules", h.SetTeamReviewRules)
+	r.Post("/team/setReviewSLA", h.SetTeamReviewSLA)
+	r.Post("/team/codeowners", h.UploadCodeOwners)
+	r.Post("/team/rules", h.UploadRuleSet)
+	r.Get("/team/rules", h.GetRuleSet)
+	r.Post("/team/rules/rollback", h.RollbackRuleSet)
+	r.Post("/operations/{id}/undo", h.UndoOperation)
 	r.Post("/users/setIsActive", h.SetUserActive)
+	r.Post("/users/setCapacity", h.SetUserCapacity)
+	r.Post("/users/setExpertise", h.SetUserExpertise)
+	r.Post("/users/setManager", h.SetUserManager)
+	r.Post("/users/setSeniority", h.SetUserSeniority)
 	r.Get("/users/getReview", h.GetUserReviews)
+	r.Post("/users/handoff", h.HandoffReviews)
+	r.Get("/users/notificationPreferences", h.GetNotificationPreferences)
+	r.Post("/users/setNotificationPreferences", h.SetNotificationPreferences)
+	r.Get("/users/digest", h.GetDigest)
+	r.Post("/users/availability", h.AddUnavailability)
+	r.Get("/users/availability", h.ListUnavailability)
+	r.Post("/users/availability/import", h.ImportUnavailability)
+	r.Post("/exclusions/add", h.CreateReviewExclusion)
+	r.Post("/exclusions/remove", h.DeleteReviewExclusion)
+	r.Get("/exclusions/list", h.ListReviewExclusions)
+	r.Post("/repository/add", h.CreateRepository)
+	r.Get("/repository/get", h.GetRepository)
+	r.Post("/repository/setOwners", h.SetRepositoryOwners)
 	r.Post("/pullRequest/create", h.CreatePR)
+	r.Post("/pullRequest/preview", h.PreviewPR)
+	r.Get("/pullRequest/get", h.GetPR)
+	r.Get("/pullRequest/history", h.GetAssignmentHistory)
 	r.Post("/pullRequest/merge", h.MergePR)
 	r.Post("/pullRequest/reassign", h.ReassignReviewer)
+	r.Post("/pullRequest/addReviewer", h.AddReviewer)
+	r.Post("/pullRequest/removeReviewer", h.RemoveReviewer)
+	r.Post("/pullRequest/decline", h.DeclineReview)
+	r.Post("/pullRequest/review", h.SubmitReview)
+	r.Get("/pullRequest/understaffed", h.ListUnderstaffedPRs)
+	r.Post("/pullRequest/backfill", h.BackfillPRs)
 	r.Get("/healthz", h.HealthCheck)
-	r.Get("/stats/assignments", h.ReviewerStats)
+	r.Get("/stats/assignments", h.AssignmentStats)
+	r.Get("/stats/pairs", h.PairStats)
+	r.Get("/stats/declines", h.DeclineStats)
+	r.Get("/stats/latency", h.LatencyStats)
 }
 
 type APIErrorResponse struct {
@@ -61,12 +102,22 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 		writeAPIError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
 	case errors.Is(err, domain.ErrConflict):
 		writeAPIError(w, http.StatusConflict, "CONFLICT", err.Error())
-	case errors.Is(err, domain.ErrReviewerExist), errors.Is(err, domain.ErrPRMerged):
+	case errors.Is(err, domain.ErrPRMerged):
 		writeAPIError(w, http.StatusConflict, "PR_MERGED", err.Error())
+	case errors.Is(err, domain.ErrReviewerExist):
+		writeAPIError(w, http.StatusConflict, "REVIEWER_EXISTS", err.Error())
+	case errors.Is(err, domain.ErrNotEligible):
+		writeAPIError(w, http.StatusConflict, "NOT_ELIGIBLE", err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		writeAPIError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
 	case errors.Is(err, domain.ErrNoCandidates):
 		writeAPIError(w, http.StatusConflict, "NO_CANDIDATE", err.Error())
+	case errors.Is(err, domain.ErrCapacityFull):
+		writeAPIError(w, http.StatusConflict, "CAPACITY_EXHAUSTED", err.Error())
 	case errors.Is(err, domain.ErrNotAssigned):
 		writeAPIError(w, http.StatusConflict, "NOT_ASSIGNED", err.Error())
+	case errors.Is(err, domain.ErrAlreadyUndone):
+		writeAPIError(w, http.StatusConflict, "ALREADY_UNDONE", err.Error())
 	default:
 		h.log.Error("internal server error", "error", err)
 		writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"reviewer/internal/digest"
+	"reviewer/internal/domain"
+)
+
+func (h *Handler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("user_id")
+	if id == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	prefs, err := h.svc.GetNotificationPreferences(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"preferences": prefs})
+}
+
+func (h *Handler) SetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	var req domain.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.UserID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	if req.Delivery == "" {
+		req.Delivery = domain.NotificationDeliveryImmediate
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if !req.Channel.Valid() {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "channel must be SLACK, EMAIL, LOG or NONE")
+		return
+	}
+	if req.Channel == domain.NotificationChannelEmail && req.Address == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "address is required for EMAIL channel")
+		return
+	}
+	if req.Channel == domain.NotificationChannelEmail {
+		if _, err := mail.ParseAddress(req.Address); err != nil {
+			writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "address must be a valid email address")
+			return
+		}
+	}
+	if !req.Delivery.Valid() {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "delivery must be IMMEDIATE or DIGEST")
+		return
+	}
+	if req.QuietHours != nil && !req.QuietHours.Valid() {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "quiet_hours must be distinct hours from 0 to 23")
+		return
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "unknown timezone")
+		return
+	}
+
+	prefs, err := h.svc.SetNotificationPreferences(r.Context(), req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"preferences": prefs})
+}
+
+// GetDigest показывает сводку пользователя в том виде, в каком она была бы отправлена сейчас:
+// format=json (по умолчанию), markdown или html
+func (h *Handler) GetDigest(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("user_id")
+	if id == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	var render func(domain.Digest) (string, error)
+	var contentType string
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+	case "markdown":
+		render, contentType = digest.Markdown, "text/markdown; charset=utf-8"
+	case "html":
+		render, contentType = digest.HTML, "text/html; charset=utf-8"
+	default:
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "format must be json, markdown or html")
+		return
+	}
+
+	d, err := h.svc.GetDigest(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	if render == nil {
+		writeJSON(w, http.StatusOK, map[string]any{"digest": d})
+		return
+	}
+	body, err := render(d)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, body)
+}
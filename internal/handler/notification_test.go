@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Notification(t *testing.T) {
+	r, _, teardown := setupIntegration(t)
+	defer teardown()
+
+	team := `{"team_name": "notify-api", "members": [{"user_id": "na", "username": "A", "is_active": true}]}`
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(team)))
+
+	t.Run("GetNotificationPreferences_Default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/notificationPreferences?user_id=na", http.NoBody))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "LOG", resp["preferences"]["channel"])
+		assert.Equal(t, "IMMEDIATE", resp["preferences"]["delivery"])
+	})
+
+	t.Run("SetNotificationPreferences", func(t *testing.T) {
+		body := `{"user_id": "na", "channel": "EMAIL", "address": "na@example.com", "delivery": "DIGEST",
+			"quiet_hours": {"start": 22, "end": 8}, "timezone": "Europe/Moscow"}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/setNotificationPreferences", bytes.NewBufferString(body)))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "DIGEST", resp["preferences"]["delivery"])
+		assert.Equal(t, map[string]any{"start": float64(22), "end": float64(8)}, resp["preferences"]["quiet_hours"])
+	})
+
+	invalid := map[string]string{
+		"UnknownChannel":  `{"user_id": "na", "channel": "PIGEON"}`,
+		"EmailNoAddress":  `{"user_id": "na", "channel": "EMAIL"}`,
+		"EmailBadAddress": `{"user_id": "na", "channel": "EMAIL", "address": "dev@example.com\r\nBcc: x@example.com"}`,
+		"EmptyQuietHours": `{"user_id": "na", "channel": "LOG", "quiet_hours": {"start": 9, "end": 9}}`,
+		"UnknownTimezone": `{"user_id": "na", "channel": "LOG", "timezone": "Mars/Olympus"}`,
+		"UnknownDelivery": `{"user_id": "na", "channel": "LOG", "delivery": "WEEKLY"}`,
+		"MissingUserID":   `{"channel": "LOG"}`,
+	}
+	for name, body := range invalid {
+		t.Run("SetNotificationPreferences_"+name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/setNotificationPreferences", bytes.NewBufferString(body)))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+	t.Run("GetDigest", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/digest?user_id=na", http.NoBody))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "na", resp["digest"]["user_id"])
+		assert.Equal(t, []any{}, resp["digest"]["open_reviews"])
+	})
+
+	t.Run("GetDigest_Markdown", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/digest?user_id=na&format=markdown", http.NoBody))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "# Review digest for na")
+	})
+
+	t.Run("GetDigest_HTML", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/digest?user_id=na&format=html", http.NoBody))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "<h1>Review digest for na</h1>")
+	})
+
+	t.Run("GetDigest_UnknownFormat", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/digest?user_id=na&format=pdf", http.NoBody))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GetDigest_UnknownUser", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/digest?user_id=missing", http.NoBody))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
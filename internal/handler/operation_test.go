@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+)
+
+func TestHandler_Operation(t *testing.T) {
+	r, _, teardown := setupIntegration(t)
+	defer teardown()
+
+	createBody := `{"team_name": "undo-api", "members": [{"user_id": "ua1", "username": "U", "is_active": true}]}`
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(createBody)))
+
+	deactW := httptest.NewRecorder()
+	r.ServeHTTP(deactW, httptest.NewRequest(http.MethodPost, "/team/deactivate", bytes.NewBufferString(`{"team_name": "undo-api"}`)))
+	var deact domain.DeactivationResult
+	require.NoError(t, json.Unmarshal(deactW.Body.Bytes(), &deact))
+
+	t.Run("UndoOperation_Success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/operations/%d/undo", deact.OperationID), http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp domain.UndoResult
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, deact.OperationID, resp.OperationID)
+		assert.Len(t, resp.ReactivatedUsers, 1)
+	})
+
+	t.Run("UndoOperation_AlreadyUndone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/operations/%d/undo", deact.OperationID), http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var resp APIErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "ALREADY_UNDONE", resp.Error.Code)
+	})
+
+	t.Run("UndoOperation_BadID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/operations/abc/undo", http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
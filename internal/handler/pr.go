@@ -6,19 +6,29 @@ import (
 	"net/http"
 
 	"reviewer/internal/domain"
+	"reviewer/internal/service"
 )
 
 func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PRID     string `json:"pull_request_id"`
-		Title    string `json:"pull_request_name"`
-		AuthorID string `json:"author_id"`
+		PRID         string   `json:"pull_request_id"`
+		Title        string   `json:"pull_request_name"`
+		AuthorID     string   `json:"author_id"`
+		Repository   string   `json:"repository"`
+		Labels       []string `json:"labels"`
+		ChangedPaths []string `json:"changed_paths"`
+		Exclude      []string `json:"exclude_reviewers"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
 		return
 	}
-	pr, err := h.svc.CreatePR(r.Context(), req.PRID, req.Title, req.AuthorID)
+	pr, err := h.svc.CreatePR(r.Context(), req.PRID, req.Title, req.AuthorID, service.CreatePROptions{
+		Repository:       req.Repository,
+		Labels:           req.Labels,
+		ChangedPaths:     req.ChangedPaths,
+		ExcludeReviewers: req.Exclude,
+	})
 	if err != nil {
 		if errors.Is(err, domain.ErrConflict) {
 			writeAPIError(w, http.StatusConflict, "PR_EXISTS", "pr already exists")
@@ -27,18 +37,97 @@ func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
 		h.handleError(w, err)
 		return
 	}
+	prResp := map[string]any{
+		"pull_request_id":    pr.ID,
+		"pull_request_name":  pr.Title,
+		"author_id":          pr.AuthorID,
+		"status":             pr.Status,
+		"assigned_reviewers": pr.Reviewers,
+		"labels":             pr.Labels,
+		"changed_paths":      pr.ChangedPaths,
+	}
+	if pr.Repository != "" {
+		prResp["repository"] = pr.Repository
+	}
+	if len(pr.ExcludedReviewers) > 0 {
+		prResp["excluded_reviewers"] = pr.ExcludedReviewers
+	}
+	if len(pr.ShadowReviewers) > 0 {
+		prResp["shadow_reviewers"] = pr.ShadowReviewers
+	}
 	resp := map[string]any{
-		"pr": map[string]any{
-			"pull_request_id":    pr.ID,
-			"pull_request_name":  pr.Title,
-			"author_id":          pr.AuthorID,
-			"status":             pr.Status,
-			"assigned_reviewers": pr.Reviewers,
-		},
+		"pr":               prResp,
+		"reviewer_matches": pr.Matches,
+	}
+	if pr.CapacityExhausted {
+		resp["capacity_exhausted"] = true
+	}
+	if len(pr.Violations) > 0 {
+		resp["constraint_violations"] = pr.Violations
+	}
+	if pr.RuleTrace != nil {
+		resp["rule_trace"] = pr.RuleTrace
 	}
 	writeJSON(w, http.StatusCreated, resp)
 }
 
+func (h *Handler) PreviewPR(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		AuthorID     string   `json:"author_id"`
+		Repository   string   `json:"repository"`
+		Labels       []string `json:"labels"`
+		ChangedPaths []string `json:"changed_paths"`
+		Exclude      []string `json:"exclude_reviewers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.AuthorID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "author_id is required")
+		return
+	}
+	preview, err := h.svc.PreviewPR(r.Context(), req.AuthorID, service.CreatePROptions{
+		Repository:       req.Repository,
+		Labels:           req.Labels,
+		ChangedPaths:     req.ChangedPaths,
+		ExcludeReviewers: req.Exclude,
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"preview": preview})
+}
+
+func (h *Handler) GetPR(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+	pr, err := h.svc.GetPR(r.Context(), prID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"pr": pr})
+}
+
+func (h *Handler) GetAssignmentHistory(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+	history, err := h.svc.GetAssignmentHistory(r.Context(), prID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"pull_request_id": prID, "assignments": history})
+}
+
 func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		PRID string `json:"pull_request_id"`
@@ -67,14 +156,19 @@ func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PRID  string `json:"pull_request_id"`
-		OldID string `json:"old_reviewer_id"`
+		PRID    string   `json:"pull_request_id"`
+		OldID   string   `json:"old_reviewer_id"`
+		NewID   string   `json:"new_reviewer_id"`
+		Exclude []string `json:"exclude_reviewers"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
 		return
 	}
-	pr, newReviewer, err := h.svc.ReassignReviewer(r.Context(), req.PRID, req.OldID)
+	pr, newReviewer, err := h.svc.ReassignReviewer(r.Context(), req.PRID, req.OldID, service.ReassignOptions{
+		ExcludeReviewers: req.Exclude,
+		NewReviewerID:    req.NewID,
+	})
 	if err != nil {
 		h.handleError(w, err)
 		return
@@ -92,3 +186,144 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	}
 	writeJSON(w, http.StatusOK, resp)
 }
+
+type reviewerChangeRequest struct {
+	PRID    string `json:"pull_request_id"`
+	UserID  string `json:"user_id"`
+	ActorID string `json:"actor_id"`
+}
+
+// decodeReviewerChange разбирает запрос ручного изменения ревьюеров; при ошибке сам пишет ответ
+func decodeReviewerChange(w http.ResponseWriter, r *http.Request) (reviewerChangeRequest, bool) {
+	var req reviewerChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return req, false
+	}
+	if req.PRID == "" || req.UserID == "" || req.ActorID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id, user_id and actor_id are required")
+		return req, false
+	}
+	return req, true
+}
+
+func (h *Handler) AddReviewer(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeReviewerChange(w, r)
+	if !ok {
+		return
+	}
+	pr, err := h.svc.AddReviewer(r.Context(), req.PRID, req.UserID, req.ActorID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"pr": pr})
+}
+
+func (h *Handler) RemoveReviewer(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeReviewerChange(w, r)
+	if !ok {
+		return
+	}
+	pr, err := h.svc.RemoveReviewer(r.Context(), req.PRID, req.UserID, req.ActorID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"pr": pr})
+}
+
+func (h *Handler) DeclineReview(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PRID   string               `json:"pull_request_id"`
+		UserID string               `json:"user_id"`
+		Reason domain.DeclineReason `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.PRID == "" || req.UserID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id and user_id are required")
+		return
+	}
+	if !req.Reason.Valid() {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "reason must be BUSY, LACKS_CONTEXT or CONFLICT")
+		return
+	}
+
+	pr, replacement, err := h.svc.DeclineReview(r.Context(), req.PRID, req.UserID, req.Reason)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	resp := map[string]any{
+		"pr": map[string]any{
+			"pull_request_id":    pr.ID,
+			"pull_request_name":  pr.Title,
+			"author_id":          pr.AuthorID,
+			"status":             pr.Status,
+			"assigned_reviewers": pr.Reviewers,
+		},
+		"replaced_by": nil,
+	}
+	if replacement != nil {
+		resp["replaced_by"] = replacement.ID
+	}
+	if len(pr.Violations) > 0 {
+		resp["constraint_violations"] = pr.Violations
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PRID     string                `json:"pull_request_id"`
+		UserID   string                `json:"user_id"`
+		Decision domain.ReviewDecision `json:"decision"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.PRID == "" || req.UserID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id and user_id are required")
+		return
+	}
+	if !req.Decision.Valid() {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "decision must be APPROVED, CHANGES_REQUESTED or COMMENTED")
+		return
+	}
+
+	review, err := h.svc.SubmitReview(r.Context(), req.PRID, req.UserID, req.Decision)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"review": review})
+}
+
+func (h *Handler) ListUnderstaffedPRs(w http.ResponseWriter, r *http.Request) {
+	prs, err := h.svc.ListUnderstaffedPRs(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"pull_requests": prs})
+}
+
+func (h *Handler) BackfillPRs(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string `json:"team_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	results, err := h.svc.BackfillUnderstaffedPRs(r.Context(), req.TeamName)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": results})
+}
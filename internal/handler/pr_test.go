@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -100,6 +101,150 @@ func TestHandler_PR(t *testing.T) {
 		assert.NotContains(t, reviewers, "re_old")
 	})
 
+	t.Run("AssignmentHistory", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr-1", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Assignments []domain.AssignmentRecord `json:"assignments"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Assignments, 2)
+		for _, a := range resp.Assignments {
+			assert.Equal(t, "auth", a.AuthorID)
+			require.NotNil(t, a.Explanation)
+			assert.Equal(t, domain.AssignmentStrategyRandom, a.Explanation.Strategy)
+			assert.Equal(t, 2, a.Explanation.PoolSize)
+		}
+		assert.Equal(t, resp.Assignments[0].Explanation.Seed, resp.Assignments[1].Explanation.Seed)
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("PreviewPR", func(t *testing.T) {
+		body := `{"author_id": "auth", "exclude_reviewers": ["r2"]}`
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/preview", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Preview domain.AssignmentPreview `json:"preview"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "pr-api", resp.Preview.TeamName)
+		require.Len(t, resp.Preview.Reviewers, 1)
+		assert.Equal(t, "r1", resp.Preview.Reviewers[0].UserID)
+		assert.Contains(t, resp.Preview.Exclusions, domain.CandidateExclusion{UserID: "r2", Reason: domain.ExclusionRequested})
+		assert.Equal(t, 1.0, resp.Preview.Probabilities["r1"])
+	})
+
+	t.Run("PreviewPR_MissingAuthor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/preview", bytes.NewBufferString(`{}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("AddAndRemoveReviewer", func(t *testing.T) {
+		_, err := repo.CreatePR(ctx, &domain.PullRequest{ID: "pr-manual", Title: "T", AuthorID: "auth", TeamName: "pr-api", Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		require.NoError(t, repo.AddReviewers(ctx, "pr-manual", []string{"r1"}))
+		post := func(path, body string) (int, APIErrorResponse) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body)))
+			var resp APIErrorResponse
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			return w.Code, resp
+		}
+
+		code, _ := post("/pullRequest/addReviewer", `{"pull_request_id": "pr-manual", "user_id": "r2", "actor_id": "auth"}`)
+		assert.Equal(t, http.StatusOK, code)
+
+		code, resp := post("/pullRequest/addReviewer", `{"pull_request_id": "pr-manual", "user_id": "r2", "actor_id": "auth"}`)
+		assert.Equal(t, http.StatusConflict, code)
+		assert.Equal(t, "REVIEWER_EXISTS", resp.Error.Code)
+
+		code, resp = post("/pullRequest/removeReviewer", `{"pull_request_id": "pr-manual", "user_id": "r2", "actor_id": "r1"}`)
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "FORBIDDEN", resp.Error.Code)
+
+		code, _ = post("/pullRequest/removeReviewer", `{"pull_request_id": "pr-manual", "user_id": "r2", "actor_id": "auth"}`)
+		assert.Equal(t, http.StatusOK, code)
+
+		code, _ = post("/pullRequest/removeReviewer", `{"pull_request_id": "pr-manual", "user_id": "r2"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("ReassignReviewer_ExplicitTarget", func(t *testing.T) {
+		_, err := repo.CreatePR(ctx, &domain.PullRequest{ID: "pr-rx", Title: "T", AuthorID: "auth", TeamName: "pr-api", Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		require.NoError(t, repo.AddReviewers(ctx, "pr-rx", []string{"r1"}))
+
+		body := `{"pull_request_id": "pr-rx", "old_reviewer_id": "r1", "new_reviewer_id": "r2"}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "r2", resp["replaced_by"])
+
+		body = `{"pull_request_id": "pr-rx", "old_reviewer_id": "r2", "new_reviewer_id": "auth"}`
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("DeclineReview", func(t *testing.T) {
+		_, err := repo.CreatePR(ctx, &domain.PullRequest{ID: "pr-decline", Title: "T", AuthorID: "auth", TeamName: "pr-api", Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		require.NoError(t, repo.AddReviewers(ctx, "pr-decline", []string{"r1"}))
+
+		body := `{"pull_request_id": "pr-decline", "user_id": "r1", "reason": "BUSY"}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pullRequest/decline", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "r2", resp["replaced_by"])
+
+		body = `{"pull_request_id": "pr-decline", "user_id": "r2", "reason": "BORED"}`
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pullRequest/decline", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("SubmitReview", func(t *testing.T) {
+		body := `{"pull_request_id": "pr-decline", "user_id": "r2", "decision": "APPROVED"}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		review := resp["review"].(map[string]any)
+		assert.Equal(t, "APPROVED", review["decision"])
+
+		body = `{"pull_request_id": "pr-decline", "user_id": "r2", "decision": "LGTM"}`
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("ReassignReviewer_Fail_Merged", func(t *testing.T) {
 		body := `{"pull_request_id": "pr-1", "old_user_id": "r1"}`
 		req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewBufferString(body))
@@ -112,4 +257,92 @@ func TestHandler_PR(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "PR_MERGED", resp.Error.Code)
 	})
+	t.Run("Understaffed_ListAndBackfill", func(t *testing.T) {
+		tName := "understaffed-api"
+		_, err := repo.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "ua_a", Username: "A", TeamName: tName, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "ua_r", Username: "R", TeamName: tName, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "pr-ua", Title: "T", AuthorID: "ua_a", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+
+		listReq := httptest.NewRequest(http.MethodGet, "/pullRequest/understaffed?team_name="+tName, http.NoBody)
+		listW := httptest.NewRecorder()
+		r.ServeHTTP(listW, listReq)
+		backfillReq := httptest.NewRequest(http.MethodPost, "/pullRequest/backfill", bytes.NewBufferString(`{"team_name": "understaffed-api"}`))
+		backfillW := httptest.NewRecorder()
+		r.ServeHTTP(backfillW, backfillReq)
+
+		assert.Equal(t, http.StatusOK, listW.Code)
+		var listResp map[string][]domain.UnderstaffedPR
+		require.NoError(t, json.Unmarshal(listW.Body.Bytes(), &listResp))
+		require.Len(t, listResp["pull_requests"], 1)
+		assert.Equal(t, "pr-ua", listResp["pull_requests"][0].ID)
+
+		assert.Equal(t, http.StatusOK, backfillW.Code)
+		var backfillResp map[string][]domain.PRBackfill
+		require.NoError(t, json.Unmarshal(backfillW.Body.Bytes(), &backfillResp))
+		require.Len(t, backfillResp["results"], 1)
+		assert.Equal(t, []string{"ua_r"}, backfillResp["results"][0].Added)
+		assert.True(t, backfillResp["results"][0].Understaffed)
+	})
+
+	t.Run("CodeOwners_UploadAndGetPR", func(t *testing.T) {
+		team := `{"team_name": "owners-api", "members": [{"user_id": "own1", "username": "O", "is_active": true}]}`
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(team)))
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		require.NoError(t, mw.WriteField("team_name", "owners-api"))
+		fw, err := mw.CreateFormFile("file", "CODEOWNERS")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte("*.proto @own1\n"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+		uploadReq := httptest.NewRequest(http.MethodPost, "/team/codeowners", &buf)
+		uploadReq.Header.Set("Content-Type", mw.FormDataContentType())
+		uploadW := httptest.NewRecorder()
+		r.ServeHTTP(uploadW, uploadReq)
+		require.Equal(t, http.StatusOK, uploadW.Code)
+		prBody := `{"pull_request_id": "pr-owners", "pull_request_name": "T", "author_id": "auth", "changed_paths": ["api/user.proto"]}`
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewBufferString(prBody)))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=pr-owners", http.NoBody))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			PR domain.PullRequest `json:"pr"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Contains(t, resp.PR.Reviewers, "own1")
+		var ownerMatch *domain.ReviewerMatch
+		for i := range resp.PR.Matches {
+			if resp.PR.Matches[i].UserID == "own1" {
+				ownerMatch = &resp.PR.Matches[i]
+			}
+		}
+		require.NotNil(t, ownerMatch)
+		assert.Equal(t, domain.MatchReasonCodeOwner, ownerMatch.Reason)
+		assert.Equal(t, "*.proto", ownerMatch.Rule.Pattern)
+	})
+
+	t.Run("CodeOwners_InvalidFile", func(t *testing.T) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		require.NoError(t, mw.WriteField("team_name", "pr-api"))
+		fw, err := mw.CreateFormFile("file", "CODEOWNERS")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte("!docs/ @r1\n"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+		req := httptest.NewRequest(http.MethodPost, "/team/codeowners", &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"reviewer/internal/domain"
+)
+
+type repositoryRequest struct {
+	Name       string   `json:"repository"`
+	OwnerTeams []string `json:"owner_teams"`
+}
+
+func decodeRepositoryRequest(w http.ResponseWriter, r *http.Request) (repositoryRequest, bool) {
+	var req repositoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return req, false
+	}
+	if req.Name == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "repository is required")
+		return req, false
+	}
+	if len(req.OwnerTeams) == 0 {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "owner_teams must not be empty")
+		return req, false
+	}
+	return req, true
+}
+
+func (h *Handler) CreateRepository(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRepositoryRequest(w, r)
+	if !ok {
+		return
+	}
+
+	repo, err := h.svc.CreateRepository(r.Context(), req.Name, req.OwnerTeams)
+	if err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			writeAPIError(w, http.StatusConflict, "REPOSITORY_EXISTS", "repository already exists")
+			return
+		}
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"repository": repo})
+}
+
+func (h *Handler) GetRepository(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("repository")
+	if name == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "repository is required")
+		return
+	}
+	repo, err := h.svc.GetRepository(r.Context(), name)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"repository": repo})
+}
+
+func (h *Handler) SetRepositoryOwners(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRepositoryRequest(w, r)
+	if !ok {
+		return
+	}
+
+	repo, err := h.svc.SetRepositoryOwners(r.Context(), req.Name, req.OwnerTeams)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"repository": repo})
+}
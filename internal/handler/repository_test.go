@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Repository(t *testing.T) {
+	r, _, teardown := setupIntegration(t)
+	defer teardown()
+
+	team := `{"team_name": "repo-api", "members": [{"user_id": "rp1", "username": "R", "is_active": true}]}`
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(team)))
+
+	t.Run("CreateRepository_Success", func(t *testing.T) {
+		body := `{"repository": "payments-api", "owner_teams": ["repo-api"]}`
+		req := httptest.NewRequest(http.MethodPost, "/repository/add", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var resp map[string]map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []any{"repo-api"}, resp["repository"]["owner_teams"])
+	})
+
+	t.Run("CreateRepository_Duplicate", func(t *testing.T) {
+		body := `{"repository": "payments-api", "owner_teams": ["repo-api"]}`
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/repository/add", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var resp APIErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "REPOSITORY_EXISTS", resp.Error.Code)
+	})
+
+	t.Run("CreateRepository_NoOwners", func(t *testing.T) {
+		body := `{"repository": "orphan", "owner_teams": []}`
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/repository/add", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GetRepository_NotFound", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/repository/get?repository=missing", http.NoBody))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
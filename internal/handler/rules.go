@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"reviewer/internal/rules"
+)
+
+func (h *Handler) UploadRuleSet(w http.ResponseWriter, r *http.Request) {
+	teamName, content, ok := readTeamFile(w, r)
+	if !ok {
+		return
+	}
+
+	saved, err := h.svc.SetRuleSet(r.Context(), teamName, content)
+	if err != nil {
+		if errors.Is(err, rules.ErrInvalidRules) {
+			writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"rule_set": saved})
+}
+
+func (h *Handler) GetRuleSet(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+	version := 0
+	if v := r.URL.Query().Get("version"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "version must be a positive integer")
+			return
+		}
+		version = parsed
+	}
+
+	ruleSet, versions, err := h.svc.GetRuleSet(r.Context(), teamName, version)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"rule_set": ruleSet, "versions": versions})
+}
+
+func (h *Handler) RollbackRuleSet(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string `json:"team_name"`
+		Version  int    `json:"version"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.TeamName == "" || req.Version <= 0 {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and positive version are required")
+		return
+	}
+
+	saved, err := h.svc.RollbackRuleSet(r.Context(), req.TeamName, req.Version)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"rule_set": saved})
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+)
+
+func uploadRuleSet(t *testing.T, h http.Handler, teamName, content string) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	require.NoError(t, mw.WriteField("team_name", teamName))
+	fw, err := mw.CreateFormFile("file", "rules.yaml")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	req := httptest.NewRequest(http.MethodPost, "/team/rules", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_RuleSet(t *testing.T) {
+	r, _, teardown := setupIntegration(t)
+	defer teardown()
+
+	team := `{"team_name": "rules-api", "members": [{"user_id": "ra1", "username": "A", "is_active": true}]}`
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(team)))
+
+	t.Run("Upload_Invalid", func(t *testing.T) {
+		w := uploadRuleSet(t, r, "rules-api", "weights:\n  - name: w\n")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UploadAndGet", func(t *testing.T) {
+		uploadW := uploadRuleSet(t, r, "rules-api", `{"weights": [{"name": "spread", "load_factor": -1}]}`)
+		require.Equal(t, http.StatusCreated, uploadW.Code)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/team/rules?team_name=rules-api", http.NoBody))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			RuleSet  domain.RuleSetVersion `json:"rule_set"`
+			Versions []int                 `json:"versions"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.RuleSet.Version)
+		assert.Equal(t, []int{1}, resp.Versions)
+	})
+
+	t.Run("Get_UnknownVersion", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/team/rules?team_name=rules-api&version=7", http.NoBody))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -1,12 +1,109 @@
 package handler
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
 
-func (h *Handler) ReviewerStats(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.svc.ReviewerStats(r.Context())
+	"reviewer/internal/domain"
+)
+
+func (h *Handler) AssignmentStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, to, err := parseTimeRange(query)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+	filter := domain.AssignmentStatsFilter{
+		From:     from,
+		To:       to,
+		TeamName: query.Get("team_name"),
+		Status:   domain.PRStatus(query.Get("status")),
+		GroupBy:  domain.StatsGroupBy(query.Get("group_by")),
+	}
+	if filter.Status != "" && filter.Status != domain.PRStatusOpen && filter.Status != domain.PRStatusMerged {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "status must be OPEN or MERGED")
+		return
+	}
+	if filter.GroupBy != "" && !filter.GroupBy.Valid() {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "group_by must be user, team or week")
+		return
+	}
+
+	stats, err := h.svc.AssignmentStats(r.Context(), filter)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]any{"stats": stats})
 }
+
+func (h *Handler) PairStats(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	pairs, err := h.svc.PairStats(r.Context(), teamName)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	matrix := make(map[string]map[string]int64)
+	for _, p := range pairs {
+		if matrix[p.AuthorID] == nil {
+			matrix[p.AuthorID] = make(map[string]int64)
+		}
+		matrix[p.AuthorID][p.ReviewerID] = p.AssignmentCount
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"pairs": pairs, "matrix": matrix})
+}
+
+func (h *Handler) DeclineStats(w http.ResponseWriter, r *http.Request) {
+	users, teams, err := h.svc.DeclineStats(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"users": users, "teams": teams})
+}
+
+func (h *Handler) LatencyStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, to, err := parseTimeRange(query)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+	teams, reviewers, err := h.svc.LatencyStats(r.Context(), domain.LatencyFilter{From: from, To: to, TeamName: query.Get("team_name")})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"teams": teams, "reviewers": reviewers})
+}
+
+// parseTimeRange разбирает необязательные параметры from и to в формате RFC 3339 или YYYY-MM-DD
+func parseTimeRange(query url.Values) (from, to *time.Time, err error) {
+	parse := func(name string) (*time.Time, error) {
+		v := query.Get(name)
+		if v == "" {
+			return nil, nil
+		}
+		for _, layout := range []string{time.RFC3339, time.DateOnly} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return &t, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+	}
+	if from, err = parse("from"); err != nil {
+		return nil, nil, err
+	}
+	if to, err = parse("to"); err != nil {
+		return nil, nil, err
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
@@ -20,4 +20,88 @@ func TestHandler_Stats(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), `"stats":[]`)
 	})
+
+	t.Run("GetStats_Filtered", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/stats/assignments?from=2025-01-01&to=2025-02-01T00:00:00Z&status=MERGED&group_by=week", http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"stats":[]`)
+	})
+
+	invalid := map[string]string{
+		"BadFrom":    "from=yesterday",
+		"EmptyRange": "from=2025-02-01&to=2025-01-01",
+		"BadStatus":  "status=CLOSED",
+		"BadGroupBy": "group_by=month",
+	}
+	for name, query := range invalid {
+		t.Run("GetStats_"+name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/stats/assignments?"+query, http.NoBody)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+
+	t.Run("GetStats_UnknownTeam", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/stats/assignments?team_name=ghost", http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("PairStats_Empty", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/stats/pairs", http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"pairs":[]`)
+	})
+
+	t.Run("PairStats_UnknownTeam", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/stats/pairs?team_name=ghost", http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("DeclineStats_Empty", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/stats/declines", http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"users": [], "teams": []}`, w.Body.String())
+	})
+
+	t.Run("LatencyStats_Empty", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/stats/latency?from=2025-01-01&to=2025-04-01", http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"teams": [], "reviewers": []}`, w.Body.String())
+	})
+
+	t.Run("LatencyStats_BadRange", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/stats/latency?from=2025-04-01&to=2025-01-01", http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("LatencyStats_UnknownTeam", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/stats/latency?team_name=ghost", http.NoBody)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
 }
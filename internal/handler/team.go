@@ -3,9 +3,17 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 
+	"reviewer/internal/codeowners"
 	"reviewer/internal/domain"
+	"reviewer/internal/service"
+)
+
+const (
+	maxCodeOwnersSize         = 1 << 20
+	defaultRotationWindowDays = 30
 )
 
 func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
@@ -80,6 +88,32 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) DeactivateTeam(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName     string `json:"team_name"`
+		Reassign     bool   `json:"reassign"`
+		FallbackTeam string `json:"fallback_team"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.TeamName == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	result, err := h.svc.DeactivateTeamAndRemoveReviews(r.Context(), req.TeamName, service.DeactivationOptions{
+		Reassign:     req.Reassign,
+		FallbackTeam: req.FallbackTeam,
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) ReactivateTeam(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		TeamName string `json:"team_name"`
 	}
@@ -92,10 +126,232 @@ func (h *Handler) DeactivateTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.svc.DeactivateTeamAndRemoveReviews(r.Context(), req.TeamName)
+	result, err := h.svc.ReactivateTeam(r.Context(), req.TeamName)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) RebalanceTeam(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName  string `json:"team_name"`
+		Tolerance *int   `json:"tolerance"`
+		DryRun    bool   `json:"dry_run"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.TeamName == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+	tolerance := service.DefaultRebalanceTolerance
+	if req.Tolerance != nil {
+		if *req.Tolerance <= 0 {
+			writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "tolerance must be positive")
+			return
+		}
+		tolerance = *req.Tolerance
+	}
+
+	result, err := h.svc.RebalanceTeam(r.Context(), req.TeamName, tolerance, req.DryRun)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, result)
 }
+
+func (h *Handler) SetTeamCapacity(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName              string `json:"team_name"`
+		DefaultMaxOpenReviews *int   `json:"default_max_open_reviews"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.TeamName == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+	if req.DefaultMaxOpenReviews != nil && *req.DefaultMaxOpenReviews < 0 {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "default_max_open_reviews must not be negative")
+		return
+	}
+
+	team, err := h.svc.SetTeamCapacity(r.Context(), req.TeamName, req.DefaultMaxOpenReviews)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"team": team})
+}
+
+func (h *Handler) SetTeamLead(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string  `json:"team_name"`
+		LeadID   *string `json:"lead_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.TeamName == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+	if req.LeadID != nil && *req.LeadID == "" {
+		req.LeadID = nil
+	}
+
+	team, err := h.svc.SetTeamLead(r.Context(), req.TeamName, req.LeadID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotTeamMember) {
+			writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"team": team})
+}
+
+func (h *Handler) SetTeamStrategy(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName   string                    `json:"team_name"`
+		Strategy   domain.AssignmentStrategy `json:"strategy"`
+		WindowDays *int                      `json:"window_days"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.TeamName == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+	if !req.Strategy.Valid() {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "strategy must be RANDOM or ROTATION")
+		return
+	}
+	windowDays := defaultRotationWindowDays
+	if req.WindowDays != nil {
+		if *req.WindowDays <= 0 {
+			writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "window_days must be positive")
+			return
+		}
+		windowDays = *req.WindowDays
+	}
+
+	team, err := h.svc.SetTeamStrategy(r.Context(), req.TeamName, req.Strategy, windowDays)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"team": team})
+}
+
+func (h *Handler) SetTeamReviewSLA(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string `json:"team_name"`
+		domain.TeamReviewSLA
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.TeamName == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+	reminder, escalation := req.ReminderHours, req.EscalationHours
+	if (reminder != nil && *reminder <= 0) || (escalation != nil && *escalation <= 0) {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "reminder_hours and escalation_hours must be positive")
+		return
+	}
+	if reminder != nil && escalation != nil && *escalation <= *reminder {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "escalation_hours must be greater than reminder_hours")
+		return
+	}
+
+	team, err := h.svc.SetTeamReviewSLA(r.Context(), req.TeamName, req.TeamReviewSLA)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"team": team})
+}
+
+func (h *Handler) SetTeamReviewRules(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string `json:"team_name"`
+		domain.TeamReviewRules
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.TeamName == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	team, err := h.svc.SetTeamReviewRules(r.Context(), req.TeamName, req.TeamReviewRules)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"team": team})
+}
+
+// readTeamFile читает multipart-форму с полями team_name и file; при ошибке уже записывает ответ 400
+func readTeamFile(w http.ResponseWriter, r *http.Request) (teamName, content string, ok bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxCodeOwnersSize)
+	if err := r.ParseMultipartForm(maxCodeOwnersSize); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid multipart form")
+		return "", "", false
+	}
+	teamName = r.FormValue("team_name")
+	if teamName == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return "", "", false
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "file is required")
+		return "", "", false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "cannot read file")
+		return "", "", false
+	}
+	return teamName, string(data), true
+}
+
+func (h *Handler) UploadCodeOwners(w http.ResponseWriter, r *http.Request) {
+	teamName, content, ok := readTeamFile(w, r)
+	if !ok {
+		return
+	}
+
+	saved, rules, err := h.svc.SetCodeOwners(r.Context(), teamName, content)
+	if err != nil {
+		if errors.Is(err, codeowners.ErrInvalidSyntax) || errors.Is(err, domain.ErrUnknownOwner) {
+			writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"team_name":  saved.TeamName,
+		"rules":      rules,
+		"updated_at": saved.UpdatedAt,
+	})
+}
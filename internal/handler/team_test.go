@@ -78,4 +78,141 @@ func TestHandler_Team(t *testing.T) {
 		assert.Len(t, resp.DeactivatedUsers, 1)
 		assert.Equal(t, "d1", resp.DeactivatedUsers[0].ID)
 	})
+	t.Run("DeactivateTeam_Reassign", func(t *testing.T) {
+		createBody := `{"team_name": "deact-re-api", "members": [
+			{"user_id": "dra", "username": "A", "is_active": true},
+			{"user_id": "drr", "username": "R", "is_active": true}
+		]}`
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(createBody)))
+		prBody := `{"pull_request_id": "pr-deact-api", "pull_request_name": "T", "author_id": "dra"}`
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewBufferString(prBody)))
+
+		body := `{"team_name": "deact-re-api", "reassign": true}`
+		req := httptest.NewRequest(http.MethodPost, "/team/deactivate", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp domain.DeactivationResult
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Reassignments, 1)
+		assert.Equal(t, []string{"drr"}, resp.Reassignments[0].Removed)
+		assert.Empty(t, resp.Reassignments[0].Replacements)
+		assert.True(t, resp.Reassignments[0].Understaffed)
+	})
+
+	t.Run("ReactivateTeam_Success", func(t *testing.T) {
+		body := `{"team_name": "deact-api"}`
+		req := httptest.NewRequest(http.MethodPost, "/team/reactivate", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp domain.UndoResult
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		require.Len(t, resp.ReactivatedUsers, 1)
+		assert.Equal(t, "d1", resp.ReactivatedUsers[0].ID)
+	})
+
+	t.Run("SetTeamStrategy_Success", func(t *testing.T) {
+		body := `{"team_name": "deact-api", "strategy": "ROTATION", "window_days": 7}`
+		req := httptest.NewRequest(http.MethodPost, "/team/setStrategy", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]domain.Team
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, domain.AssignmentStrategyRotation, resp["team"].AssignmentStrategy)
+		assert.Equal(t, 7, resp["team"].RotationWindowDays)
+	})
+
+	t.Run("SetTeamReviewRules_Success", func(t *testing.T) {
+		body := `{"team_name": "deact-api", "require_senior": true, "shadow_reviewer": true}`
+		req := httptest.NewRequest(http.MethodPost, "/team/setReviewRules", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]domain.Team
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TeamReviewRules{RequireSenior: true, ShadowReviewer: true}, resp["team"].ReviewRules)
+	})
+
+	t.Run("SetTeamLead", func(t *testing.T) {
+		body := `{"team_name": "api-team", "lead_id": "u1"}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/team/setLead", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]domain.Team
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.NotNil(t, resp["team"].LeadID)
+		assert.Equal(t, "u1", *resp["team"].LeadID)
+
+		body = `{"team_name": "deact-api", "lead_id": "u1"}`
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/team/setLead", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("SetTeamStrategy_Unknown", func(t *testing.T) {
+		body := `{"team_name": "deact-api", "strategy": "ROUND_ROBIN"}`
+		req := httptest.NewRequest(http.MethodPost, "/team/setStrategy", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("SetTeamReviewSLA", func(t *testing.T) {
+		body := `{"team_name": "api-team", "reminder_hours": 24, "escalation_hours": 72}`
+		req := httptest.NewRequest(http.MethodPost, "/team/setReviewSLA", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		sla := resp["team"].(map[string]any)["review_sla"].(map[string]any)
+		assert.Equal(t, float64(24), sla["reminder_hours"])
+		assert.Equal(t, float64(72), sla["escalation_hours"])
+	})
+
+	t.Run("SetTeamReviewSLA_EscalationBeforeReminder", func(t *testing.T) {
+		body := `{"team_name": "api-team", "reminder_hours": 24, "escalation_hours": 12}`
+		req := httptest.NewRequest(http.MethodPost, "/team/setReviewSLA", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("RebalanceTeam_DryRun", func(t *testing.T) {
+		body := `{"team_name": "api-team", "dry_run": true}`
+		req := httptest.NewRequest(http.MethodPost, "/team/rebalance", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, true, resp["dry_run"])
+		assert.Equal(t, float64(1), resp["tolerance"])
+		assert.Equal(t, []any{}, resp["moves"])
+	})
+
+	t.Run("RebalanceTeam_InvalidTolerance", func(t *testing.T) {
+		body := `{"team_name": "api-team", "tolerance": 0}`
+		req := httptest.NewRequest(http.MethodPost, "/team/rebalance", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
@@ -2,19 +2,41 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/ical"
 )
 
+const maxCalendarSize = 1 << 20
+
 func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		UserID   string `json:"user_id"`
-		IsActive bool   `json:"is_active"`
+		UserID          string `json:"user_id"`
+		IsActive        bool   `json:"is_active"`
+		ReassignReviews bool   `json:"reassign_reviews"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
 		return
 	}
 
+	if !req.IsActive && req.ReassignReviews {
+		result, err := h.svc.DeactivateUserAndReassignReviews(r.Context(), req.UserID)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"user":          result.DeactivatedUsers[0],
+			"affected_prs":  result.AffectedPRs,
+			"reassignments": result.Reassignments,
+		})
+		return
+	}
+
 	user, err := h.svc.UpdateUser(r.Context(), req.UserID, &req.IsActive)
 	if err != nil {
 		h.handleError(w, err)
@@ -23,6 +45,109 @@ func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"user": user})
 }
 
+func (h *Handler) SetUserCapacity(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID         string `json:"user_id"`
+		MaxOpenReviews *int   `json:"max_open_reviews"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.UserID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	if req.MaxOpenReviews != nil && *req.MaxOpenReviews < 0 {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "max_open_reviews must not be negative")
+		return
+	}
+
+	user, err := h.svc.SetUserCapacity(r.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"user": user})
+}
+
+func (h *Handler) SetUserExpertise(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID        string   `json:"user_id"`
+		ExpertiseTags []string `json:"expertise_tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.UserID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	user, err := h.svc.SetUserExpertise(r.Context(), req.UserID, req.ExpertiseTags)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"user": user})
+}
+
+func (h *Handler) SetUserSeniority(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID    string           `json:"user_id"`
+		Seniority domain.Seniority `json:"seniority"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.UserID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	if req.Seniority != "" && !req.Seniority.Valid() {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "seniority must be JUNIOR, MID or SENIOR")
+		return
+	}
+
+	user, err := h.svc.SetUserSeniority(r.Context(), req.UserID, req.Seniority)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"user": user})
+}
+
+func (h *Handler) SetUserManager(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID    string  `json:"user_id"`
+		ManagerID *string `json:"manager_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.UserID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	if req.ManagerID != nil && *req.ManagerID == "" {
+		req.ManagerID = nil
+	}
+
+	user, err := h.svc.SetUserManager(r.Context(), req.UserID, req.ManagerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrManagerCycle) {
+			writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"user": user})
+}
+
 func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("user_id")
 	if id == "" {
@@ -39,3 +164,115 @@ func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 		"pull_requests": prs,
 	})
 }
+
+func (h *Handler) AddUnavailability(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string    `json:"user_id"`
+		From   time.Time `json:"from"`
+		To     time.Time `json:"to"`
+		Reason string    `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.UserID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	if !req.To.After(req.From) {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "to must be after from")
+		return
+	}
+
+	period, err := h.svc.AddUnavailability(r.Context(), domain.Unavailability{
+		UserID: req.UserID,
+		From:   req.From,
+		To:     req.To,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"unavailability": period})
+}
+
+func (h *Handler) ListUnavailability(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("user_id")
+	if id == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	periods, err := h.svc.ListUnavailability(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"user_id":        id,
+		"unavailability": periods,
+	})
+}
+
+func (h *Handler) ImportUnavailability(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxCalendarSize)
+	if err := r.ParseMultipartForm(maxCalendarSize); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid multipart form")
+		return
+	}
+	userID := r.FormValue("user_id")
+	if userID == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "file is required")
+		return
+	}
+	defer file.Close()
+
+	events, err := ical.ParseEvents(file)
+	if err != nil {
+		if errors.Is(err, ical.ErrInvalidCalendar) {
+			writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+		h.handleError(w, err)
+		return
+	}
+
+	created, skipped, err := h.svc.ImportUnavailability(r.Context(), userID, events)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"user_id":        userID,
+		"unavailability": created,
+		"skipped_events": skipped,
+	})
+}
+
+func (h *Handler) HandoffReviews(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+		Target string `json:"target"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if req.UserID == "" || req.Target == "" {
+		writeAPIError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id and target are required")
+		return
+	}
+
+	result, err := h.svc.HandoffReviews(r.Context(), req.UserID, req.Target)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -46,4 +47,161 @@ func TestHandler_User(t *testing.T) {
 		assert.Equal(t, "u100", resp["user_id"])
 		assert.NotNil(t, resp["pull_requests"])
 	})
+	t.Run("SetUserActive_ReassignReviews", func(t *testing.T) {
+		team := `{"team_name": "user-re-api", "members": [
+			{"user_id": "ura", "username": "A", "is_active": true},
+			{"user_id": "urr", "username": "R", "is_active": true}
+		]}`
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(team)))
+		prBody := `{"pull_request_id": "pr-user-re", "pull_request_name": "T", "author_id": "ura"}`
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewBufferString(prBody)))
+
+		reqBody := `{"user_id": "urr", "is_active": false, "reassign_reviews": true}`
+		req := httptest.NewRequest(http.MethodPost, "/users/setIsActive", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		user := resp["user"].(map[string]any)
+		assert.Equal(t, false, user["is_active"])
+		assert.Len(t, resp["affected_prs"], 1)
+		reassignments := resp["reassignments"].([]any)
+		require.Len(t, reassignments, 1)
+		assert.Equal(t, true, reassignments[0].(map[string]any)["understaffed"])
+	})
+	t.Run("AddUnavailability_Success", func(t *testing.T) {
+		reqBody := `{"user_id": "u100", "from": "2030-01-01T00:00:00Z", "to": "2030-01-10T00:00:00Z", "reason": "vacation"}`
+		req := httptest.NewRequest(http.MethodPost, "/users/availability", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var resp map[string]any
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		period := resp["unavailability"].(map[string]any)
+		assert.Equal(t, "vacation", period["reason"])
+	})
+
+	t.Run("AddUnavailability_InvalidPeriod", func(t *testing.T) {
+		reqBody := `{"user_id": "u100", "from": "2030-01-10T00:00:00Z", "to": "2030-01-01T00:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPost, "/users/availability", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("ImportUnavailability_ICS", func(t *testing.T) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		require.NoError(t, mw.WriteField("user_id", "u100"))
+		fw, err := mw.CreateFormFile("file", "leave.ics")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Conference\r\nDTSTART;VALUE=DATE:20300201\r\nDTEND;VALUE=DATE:20300204\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+		req := httptest.NewRequest(http.MethodPost, "/users/availability/import", &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		listW := httptest.NewRecorder()
+		r.ServeHTTP(listW, httptest.NewRequest(http.MethodGet, "/users/availability?user_id=u100", http.NoBody))
+		var resp map[string]any
+		err = json.Unmarshal(listW.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Len(t, resp["unavailability"], 2)
+	})
+
+	t.Run("SetUserCapacity", func(t *testing.T) {
+		reqBody := `{"user_id": "u100", "max_open_reviews": 2}`
+		req := httptest.NewRequest(http.MethodPost, "/users/setCapacity", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		user := resp["user"].(map[string]any)
+		assert.EqualValues(t, 2, user["max_open_reviews"])
+	})
+
+	t.Run("SetUserCapacity_Negative", func(t *testing.T) {
+		reqBody := `{"user_id": "u100", "max_open_reviews": -1}`
+		req := httptest.NewRequest(http.MethodPost, "/users/setCapacity", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("SetUserExpertise", func(t *testing.T) {
+		reqBody := `{"user_id": "u100", "expertise_tags": ["Frontend", "db", "db"]}`
+		req := httptest.NewRequest(http.MethodPost, "/users/setExpertise", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		user := resp["user"].(map[string]any)
+		assert.Equal(t, []any{"db", "frontend"}, user["expertise_tags"])
+	})
+
+	t.Run("SetUserSeniority", func(t *testing.T) {
+		reqBody := `{"user_id": "u100", "seniority": "SENIOR"}`
+		req := httptest.NewRequest(http.MethodPost, "/users/setSeniority", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		user := resp["user"].(map[string]any)
+		assert.Equal(t, "SENIOR", user["seniority"])
+	})
+
+	t.Run("SetUserSeniority_Invalid", func(t *testing.T) {
+		reqBody := `{"user_id": "u100", "seniority": "LEAD"}`
+		req := httptest.NewRequest(http.MethodPost, "/users/setSeniority", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("HandoffReviews", func(t *testing.T) {
+		reqBody := `{"user_id": "u100", "target": "auto"}`
+		req := httptest.NewRequest(http.MethodPost, "/users/handoff", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "u100", resp["user_id"])
+		assert.Equal(t, []any{}, resp["outcomes"])
+	})
+
+	t.Run("HandoffReviews_MissingTarget", func(t *testing.T) {
+		reqBody := `{"user_id": "u100"}`
+		req := httptest.NewRequest(http.MethodPost, "/users/handoff", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
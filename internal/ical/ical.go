@@ -0,0 +1,180 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Event struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+var ErrInvalidCalendar = errors.New("invalid icalendar data")
+
+// ParseEvents разбирает VEVENT-компоненты календаря в формате RFC 5545.
+// Поддерживаются DTSTART/DTEND (UTC, TZID, VALUE=DATE), DURATION и SUMMARY
+func ParseEvents(r io.Reader) ([]Event, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events   []Event
+		inEvent  bool
+		hasStart bool
+		allDay   bool
+		duration time.Duration
+		current  Event
+	)
+	for _, line := range lines {
+		name, params, value, ok := splitProperty(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, hasStart, allDay, duration = true, false, false, 0
+			current = Event{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if !inEvent || !hasStart {
+				return nil, fmt.Errorf("%w: event without DTSTART", ErrInvalidCalendar)
+			}
+			if current.End.IsZero() {
+				switch {
+				case duration > 0:
+					current.End = current.Start.Add(duration)
+				case allDay:
+					current.End = current.Start.AddDate(0, 0, 1)
+				default:
+					current.End = current.Start
+				}
+			}
+			events = append(events, current)
+			inEvent = false
+		case !inEvent:
+			continue
+		case name == "DTSTART":
+			current.Start, allDay, err = parseDateTime(params, value)
+			if err != nil {
+				return nil, err
+			}
+			hasStart = true
+		case name == "DTEND":
+			current.End, _, err = parseDateTime(params, value)
+			if err != nil {
+				return nil, err
+			}
+		case name == "DURATION":
+			duration, err = parseDuration(value)
+			if err != nil {
+				return nil, err
+			}
+		case name == "SUMMARY":
+			current.Summary = unescapeText(value)
+		}
+	}
+	if inEvent {
+		return nil, fmt.Errorf("%w: unterminated VEVENT", ErrInvalidCalendar)
+	}
+	return events, nil
+}
+
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+	return lines, nil
+}
+
+func splitProperty(line string) (name string, params map[string]string, value string, ok bool) {
+	colon := strings.IndexByte(line, ':')
+	if colon < 0 {
+		return "", nil, "", false
+	}
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	params = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if k, v, found := strings.Cut(p, "="); found {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+func parseDateTime(params map[string]string, value string) (t time.Time, allDay bool, err error) {
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err = time.ParseInLocation("20060102", value, time.UTC)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: bad date %q", ErrInvalidCalendar, value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse("20060102T150405Z", value)
+	} else {
+		loc := time.UTC
+		if tzid := params["TZID"]; tzid != "" {
+			if l, lerr := time.LoadLocation(tzid); lerr == nil {
+				loc = l
+			}
+		}
+		t, err = time.ParseInLocation("20060102T150405", value, loc)
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: bad date-time %q", ErrInvalidCalendar, value)
+	}
+	return t.UTC(), false, nil
+}
+
+var durationRe = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+func parseDuration(value string) (time.Duration, error) {
+	m := durationRe.FindStringSubmatch(value)
+	if m == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("%w: bad duration %q", ErrInvalidCalendar, value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("%w: bad duration %q", ErrInvalidCalendar, value)
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		return 0, fmt.Errorf("%w: negative duration %q", ErrInvalidCalendar, value)
+	}
+	return d, nil
+}
+
+func unescapeText(s string) string {
+	r := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return r.Replace(s)
+}
@@ -0,0 +1,69 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEvents(t *testing.T) {
+	t.Run("UTCAndFoldedSummary", func(t *testing.T) {
+		data := "BEGIN:VCALENDAR\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:Vacation\\, sea\r\n" +
+			" side\r\n" +
+			"DTSTART:20251020T090000Z\r\n" +
+			"DTEND:20251024T180000Z\r\n" +
+			"END:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+
+		events, err := ParseEvents(strings.NewReader(data))
+
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "Vacation, seaside", events[0].Summary)
+		assert.Equal(t, time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC), events[0].Start)
+		assert.Equal(t, time.Date(2025, 10, 24, 18, 0, 0, 0, time.UTC), events[0].End)
+	})
+
+	t.Run("AllDayWithoutEnd", func(t *testing.T) {
+		data := "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20251101\nSUMMARY:Day off\nEND:VEVENT\n"
+
+		events, err := ParseEvents(strings.NewReader(data))
+
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), events[0].Start)
+		assert.Equal(t, time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC), events[0].End)
+	})
+
+	t.Run("TZIDAndDuration", func(t *testing.T) {
+		data := "BEGIN:VEVENT\nDTSTART;TZID=Europe/Moscow:20251103T100000\nDURATION:P1DT2H\nEND:VEVENT\n"
+
+		events, err := ParseEvents(strings.NewReader(data))
+
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, time.Date(2025, 11, 3, 7, 0, 0, 0, time.UTC), events[0].Start)
+		assert.Equal(t, time.Date(2025, 11, 4, 9, 0, 0, 0, time.UTC), events[0].End)
+	})
+
+	t.Run("MissingStart", func(t *testing.T) {
+		data := "BEGIN:VEVENT\nSUMMARY:Broken\nEND:VEVENT\n"
+
+		_, err := ParseEvents(strings.NewReader(data))
+
+		require.ErrorIs(t, err, ErrInvalidCalendar)
+	})
+
+	t.Run("Unterminated", func(t *testing.T) {
+		data := "BEGIN:VEVENT\nDTSTART:20251020T090000Z\n"
+
+		_, err := ParseEvents(strings.NewReader(data))
+
+		require.ErrorIs(t, err, ErrInvalidCalendar)
+	})
+}
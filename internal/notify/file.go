@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"reviewer/internal/domain"
+)
+
+// File дописывает уведомления в локальный файл по одному JSON-объекту на строку
+type File struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+func (f *File) Notify(_ context.Context, n domain.Notification, _ domain.NotificationPreferences) error {
+	line, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("encoding notification: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening notification file: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("writing notification: %w", err)
+	}
+	return file.Close()
+}
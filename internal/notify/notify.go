@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"reviewer/internal/domain"
+)
+
+// Notifier доставляет уведомление получателю по его настройкам
+type Notifier interface {
+	Notify(ctx context.Context, n domain.Notification, to domain.NotificationPreferences) error
+}
+
+// Nop отбрасывает уведомления; используется, когда доставка не настроена
+type Nop struct{}
+
+func (Nop) Notify(context.Context, domain.Notification, domain.NotificationPreferences) error {
+	return nil
+}
+
+// Log пишет уведомления в журнал приложения
+type Log struct {
+	log *slog.Logger
+}
+
+func NewLog(log *slog.Logger) *Log {
+	return &Log{log: log}
+}
+
+func (l *Log) Notify(ctx context.Context, n domain.Notification, _ domain.NotificationPreferences) error {
+	l.log.InfoContext(ctx, "notification", "kind", n.Kind, "user_id", n.UserID, "pull_request_id", n.PullRequestID, "text", n.Text)
+	return nil
+}
+
+// Router выбирает реализацию по каналу из настроек получателя. Для канала NONE
+// уведомление считается доставленным
+type Router map[domain.NotificationChannel]Notifier
+
+func (r Router) Notify(ctx context.Context, n domain.Notification, to domain.NotificationPreferences) error {
+	if to.Channel == domain.NotificationChannelNone {
+		return nil
+	}
+	notifier, ok := r[to.Channel]
+	if !ok {
+		return fmt.Errorf("channel %s is not configured", to.Channel)
+	}
+	return notifier.Notify(ctx, n, to)
+}
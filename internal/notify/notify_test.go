@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+)
+
+type recorder struct {
+	sent []domain.Notification
+}
+
+func (r *recorder) Notify(_ context.Context, n domain.Notification, _ domain.NotificationPreferences) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func TestNotifiers(t *testing.T) {
+	ctx := context.Background()
+	n := domain.Notification{UserID: "u1", Kind: domain.NotificationReviewAssigned, PullRequestID: "pr-1", Text: "You were assigned to review pr-1"}
+
+	t.Run("Router", func(t *testing.T) {
+		slack := &recorder{}
+		router := Router{domain.NotificationChannelSlack: slack}
+
+		require.NoError(t, router.Notify(ctx, n, domain.NotificationPreferences{Channel: domain.NotificationChannelSlack}))
+		require.NoError(t, router.Notify(ctx, n, domain.NotificationPreferences{Channel: domain.NotificationChannelNone}))
+		err := router.Notify(ctx, n, domain.NotificationPreferences{Channel: domain.NotificationChannelEmail})
+
+		assert.Error(t, err)
+		assert.Len(t, slack.sent, 1)
+	})
+
+	t.Run("Slack", func(t *testing.T) {
+		var payload map[string]string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		}))
+		defer srv.Close()
+
+		err := NewSlack(srv.URL, srv.Client()).Notify(ctx, n, domain.NotificationPreferences{Address: "U123"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "<@U123> You were assigned to review pr-1", payload["text"])
+	})
+
+	t.Run("Slack_ErrorStatus", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		err := NewSlack(srv.URL, srv.Client()).Notify(ctx, n, domain.NotificationPreferences{})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("SMTP", func(t *testing.T) {
+		var gotTo []string
+		var gotMsg string
+		s := NewSMTP(SMTPConfig{Addr: "mail.local:25", From: "reviewer@example.com"})
+		s.send = func(_ string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+			gotTo, gotMsg = to, string(msg)
+			return nil
+		}
+
+		err := s.Notify(ctx, n, domain.NotificationPreferences{Address: "dev@example.com"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dev@example.com"}, gotTo)
+		assert.Contains(t, gotMsg, "Subject: review assigned: pr-1\r\n")
+		assert.Contains(t, gotMsg, n.Text)
+	})
+
+	t.Run("SMTP_HTML", func(t *testing.T) {
+		var gotMsg string
+		s := NewSMTP(SMTPConfig{Addr: "mail.local:25", From: "reviewer@example.com"})
+		s.send = func(_ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+			gotMsg = string(msg)
+			return nil
+		}
+		digest := domain.Notification{UserID: "u1", Kind: domain.NotificationDigest, Text: "# Digest", HTML: "<h1>Digest</h1>"}
+
+		err := s.Notify(ctx, digest, domain.NotificationPreferences{Address: "dev@example.com"})
+
+		require.NoError(t, err)
+		assert.Contains(t, gotMsg, "Subject: digest\r\n")
+		assert.Contains(t, gotMsg, "Content-Type: multipart/alternative; boundary=")
+		assert.Contains(t, gotMsg, "# Digest")
+		assert.Contains(t, gotMsg, "<h1>Digest</h1>")
+	})
+
+	t.Run("SMTP_HeaderInjection", func(t *testing.T) {
+		var gotMsg string
+		s := NewSMTP(SMTPConfig{Addr: "mail.local:25", From: "reviewer@example.com"})
+		s.send = func(_ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+			gotMsg = string(msg)
+			return nil
+		}
+		evil := n
+		evil.PullRequestID = "pr-1\r\nBcc: attacker@example.com"
+
+		err := s.Notify(ctx, evil, domain.NotificationPreferences{Address: "dev@example.com"})
+
+		require.NoError(t, err)
+		assert.NotContains(t, gotMsg, "\r\nBcc:")
+		assert.Contains(t, gotMsg, "Subject: =?utf-8?q?")
+	})
+
+	t.Run("SMTP_InvalidAddress", func(t *testing.T) {
+		s := NewSMTP(SMTPConfig{Addr: "mail.local:25"})
+		s.send = func(string, smtp.Auth, string, []string, []byte) error {
+			t.Fatal("message must not be sent")
+			return nil
+		}
+
+		err := s.Notify(ctx, n, domain.NotificationPreferences{Address: "dev@example.com\r\nBcc: attacker@example.com"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("SMTP_NoAddress", func(t *testing.T) {
+		err := NewSMTP(SMTPConfig{Addr: "mail.local:25"}).Notify(ctx, n, domain.NotificationPreferences{})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notifications.jsonl")
+		f := NewFile(path)
+
+		require.NoError(t, f.Notify(ctx, n, domain.NotificationPreferences{}))
+		require.NoError(t, f.Notify(ctx, n, domain.NotificationPreferences{}))
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		var lines []domain.Notification
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var got domain.Notification
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &got))
+			lines = append(lines, got)
+		}
+		assert.Equal(t, []domain.Notification{n, n}, lines)
+	})
+}
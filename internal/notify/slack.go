@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"reviewer/internal/domain"
+)
+
+// Slack отправляет уведомления в Slack-совместимый incoming webhook. Если в настройках
+// получателя указан идентификатор участника, он упоминается в сообщении
+type Slack struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func NewSlack(webhookURL string, client *http.Client) *Slack {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Slack{webhookURL: webhookURL, client: client}
+}
+
+func (s *Slack) Notify(ctx context.Context, n domain.Notification, to domain.NotificationPreferences) error {
+	text := n.Text
+	if to.Address != "" {
+		text = fmt.Sprintf("<@%s> %s", to.Address, text)
+	}
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("encoding slack message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending slack message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("slack webhook responded with %s", resp.Status)
+	}
+	return nil
+}
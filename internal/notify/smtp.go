@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+
+	"reviewer/internal/domain"
+)
+
+type SMTPConfig struct {
+	// Addr — host:port почтового сервера
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// SMTP отправляет уведомления письмом на адрес из настроек получателя
+type SMTP struct {
+	cfg  SMTPConfig
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTP(cfg SMTPConfig) *SMTP {
+	return &SMTP{cfg: cfg, send: smtp.SendMail}
+}
+
+func (s *SMTP) Notify(_ context.Context, n domain.Notification, to domain.NotificationPreferences) error {
+	if to.Address == "" {
+		return errors.New("email address is not set")
+	}
+	rcpt, err := mail.ParseAddress(to.Address)
+	if err != nil {
+		return fmt.Errorf("invalid email address: %w", err)
+	}
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		host, _, _ := strings.Cut(s.cfg.Addr, ":")
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, host)
+	}
+	// тема собирается из данных PR, поэтому кодируется: переводы строк в ней не должны стать заголовками
+	header := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n", s.cfg.From, rcpt, mime.QEncoding.Encode("utf-8", subject(n)))
+	body, contentType, err := messageBody(n)
+	if err != nil {
+		return err
+	}
+	msg := header + "MIME-Version: 1.0\r\nContent-Type: " + contentType + "\r\n\r\n" + body
+	if err := s.send(s.cfg.Addr, auth, s.cfg.From, []string{rcpt.Address}, []byte(msg)); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	return nil
+}
+
+// messageBody возвращает текст письма, а при наличии HTML-версии — multipart/alternative с обеими
+func messageBody(n domain.Notification) (body, contentType string, err error) {
+	if n.HTML == "" {
+		return n.Text + "\r\n", "text/plain; charset=UTF-8", nil
+	}
+	var b strings.Builder
+	w := multipart.NewWriter(&b)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", n.Text},
+		{"text/html; charset=UTF-8", n.HTML},
+	} {
+		pw, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return "", "", fmt.Errorf("building email: %w", err)
+		}
+		if _, err := io.WriteString(pw, part.content); err != nil {
+			return "", "", fmt.Errorf("building email: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", "", fmt.Errorf("building email: %w", err)
+	}
+	return b.String(), "multipart/alternative; boundary=" + w.Boundary(), nil
+}
+
+func subject(n domain.Notification) string {
+	kind := strings.ReplaceAll(strings.ToLower(string(n.Kind)), "_", " ")
+	if n.PullRequestID == "" {
+		return kind
+	}
+	return fmt.Sprintf("%s: %s", kind, n.PullRequestID)
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"reviewer/internal/domain"
+)
+
+func (r *repositoryImpl) CreateUnavailability(ctx context.Context, u domain.Unavailability) (domain.Unavailability, error) {
+	q := `
+		INSERT INTO user_unavailability (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, starts_at, ends_at, reason, released_at
+	`
+	var out domain.Unavailability
+	err := r.getQuerier(ctx).QueryRow(ctx, q, u.UserID, u.From, u.To, u.Reason).
+		Scan(&out.ID, &out.UserID, &out.From, &out.To, &out.Reason, &out.ReleasedAt)
+	return out, r.handleError(err)
+}
+
+func (r *repositoryImpl) ListUnavailability(ctx context.Context, userID string) ([]domain.Unavailability, error) {
+	q := `
+		SELECT id, user_id, starts_at, ends_at, reason, released_at
+		FROM user_unavailability
+		WHERE user_id = $1
+		ORDER BY starts_at, id
+	`
+	return r.queryUnavailability(ctx, q, userID)
+}
+
+func (r *repositoryImpl) ListAbsencesToRelease(ctx context.Context, now time.Time, minDuration time.Duration) ([]domain.Unavailability, error) {
+	q := `
+		SELECT id, user_id, starts_at, ends_at, reason, released_at
+		FROM user_unavailability
+		WHERE released_at IS NULL
+		  AND starts_at <= $1
+		  AND ends_at > $1
+		  AND ends_at - starts_at >= $2
+		ORDER BY starts_at, id
+		FOR UPDATE SKIP LOCKED
+	`
+	return r.queryUnavailability(ctx, q, now, minDuration)
+}
+
+func (r *repositoryImpl) queryUnavailability(ctx context.Context, q string, args ...any) ([]domain.Unavailability, error) {
+	rows, err := r.getQuerier(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	periods := make([]domain.Unavailability, 0)
+	for rows.Next() {
+		var u domain.Unavailability
+		if err := rows.Scan(&u.ID, &u.UserID, &u.From, &u.To, &u.Reason, &u.ReleasedAt); err != nil {
+			return nil, r.handleError(err)
+		}
+		periods = append(periods, u)
+	}
+	return periods, nil
+}
+
+func (r *repositoryImpl) MarkUnavailabilityReleased(ctx context.Context, id int64, at time.Time) (time.Time, error) {
+	q := `UPDATE user_unavailability SET released_at = $2 WHERE id = $1 RETURNING released_at`
+	var t time.Time
+	err := r.getQuerier(ctx).QueryRow(ctx, q, id, at).Scan(&t)
+	if err != nil {
+		return time.Time{}, r.handleError(err)
+	}
+	return t, nil
+}
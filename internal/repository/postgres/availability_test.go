@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestRepository_Availability(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+
+	tName := "avail-repo"
+	_, err = repo.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	_, err = repo.CreateUser(ctx, domain.User{ID: "av1", Username: "A1", TeamName: tName, IsActive: true})
+	require.NoError(t, err)
+	_, err = repo.CreateUser(ctx, domain.User{ID: "av2", Username: "A2", TeamName: tName, IsActive: true})
+	require.NoError(t, err)
+	now := time.Now()
+
+	t.Run("CreateAndListUnavailability", func(t *testing.T) {
+		created, err := repo.CreateUnavailability(ctx, domain.Unavailability{
+			UserID: "av1", From: now.Add(-time.Hour), To: now.Add(72 * time.Hour), Reason: "vacation",
+		})
+		require.NoError(t, err)
+
+		list, err := repo.ListUnavailability(ctx, "av1")
+
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, created.ID, list[0].ID)
+		assert.Equal(t, "vacation", list[0].Reason)
+		assert.Nil(t, list[0].ReleasedAt)
+	})
+
+	t.Run("GetActiveTeamMembers_SkipsAbsent", func(t *testing.T) {
+		users, err := repo.GetActiveTeamMembers(ctx, tName, now)
+
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, "av2", users[0].ID)
+	})
+
+	t.Run("GetAvailableUsers_AfterAbsenceEnds", func(t *testing.T) {
+		users, err := repo.GetAvailableUsers(ctx, []string{"av1", "av2"}, now.Add(73*time.Hour))
+
+		require.NoError(t, err)
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("ListAbsencesToRelease", func(t *testing.T) {
+		_, err := repo.CreateUnavailability(ctx, domain.Unavailability{
+			UserID: "av2", From: now.Add(-time.Hour), To: now.Add(time.Hour), Reason: "short",
+		})
+		require.NoError(t, err)
+
+		absences, err := repo.ListAbsencesToRelease(ctx, now, 24*time.Hour)
+
+		require.NoError(t, err)
+		require.Len(t, absences, 1)
+		assert.Equal(t, "av1", absences[0].UserID)
+
+		releasedAt, err := repo.MarkUnavailabilityReleased(ctx, absences[0].ID, now)
+		require.NoError(t, err)
+		assert.WithinDuration(t, now, releasedAt, time.Millisecond)
+		absences, err = repo.ListAbsencesToRelease(ctx, now, 24*time.Hour)
+		require.NoError(t, err)
+		assert.Empty(t, absences)
+	})
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"reviewer/internal/domain"
+)
+
+func (r *repositoryImpl) SaveCodeOwners(ctx context.Context, teamName, content string) (domain.CodeOwnersFile, error) {
+	q := `
+		INSERT INTO team_codeowners (team_name, content) VALUES ($1, $2)
+		ON CONFLICT (team_name) DO UPDATE SET content = EXCLUDED.content, updated_at = NOW()
+		RETURNING team_name, content, updated_at
+	`
+	var f domain.CodeOwnersFile
+	err := r.getQuerier(ctx).QueryRow(ctx, q, teamName, content).Scan(&f.TeamName, &f.Content, &f.UpdatedAt)
+	if err != nil {
+		return domain.CodeOwnersFile{}, r.handleError(err)
+	}
+	return f, nil
+}
+
+func (r *repositoryImpl) ListCodeOwners(ctx context.Context) ([]domain.CodeOwnersFile, error) {
+	q := `SELECT team_name, content, updated_at FROM team_codeowners ORDER BY team_name`
+	rows, err := r.getQuerier(ctx).Query(ctx, q)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	files := make([]domain.CodeOwnersFile, 0)
+	for rows.Next() {
+		var f domain.CodeOwnersFile
+		if err := rows.Scan(&f.TeamName, &f.Content, &f.UpdatedAt); err != nil {
+			return nil, r.handleError(err)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"reviewer/internal/domain"
+)
+
+// ListAwaitingPRs возвращает открытые PR автора, у которых назначены не все ревьюеры
+// или кто-то из назначенных ещё не одобрил PR
+func (r *repositoryImpl) ListAwaitingPRs(ctx context.Context, authorID string) ([]domain.AwaitingPR, error) {
+	q := `
+		SELECT pr.id, pr.title, pr.author_id, pr.status, pr.created_at, pr.needed_reviewers,
+		       COALESCE(array_agg(prr.user_id ORDER BY prr.user_id) FILTER (WHERE prr.user_id IS NOT NULL), '{}'),
+		       COALESCE(array_agg(prr.user_id ORDER BY prr.user_id) FILTER (WHERE prr.user_id IS NOT NULL AND NOT EXISTS (
+		           SELECT 1 FROM review_decisions d
+		           WHERE d.pr_id = pr.id AND d.reviewer_id = prr.user_id AND d.decision = 'APPROVED'
+		       )), '{}')
+		FROM pull_requests pr
+		LEFT JOIN pr_reviewers prr ON prr.pr_id = pr.id
+		WHERE pr.author_id = $1 AND pr.status = 'OPEN'
+		GROUP BY pr.id
+		ORDER BY pr.created_at, pr.id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, authorID)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	prs := make([]domain.AwaitingPR, 0)
+	for rows.Next() {
+		var pr domain.AwaitingPR
+		if err := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.NeededReviewers,
+			&pr.Reviewers, &pr.AwaitingReviewers); err != nil {
+			return nil, r.handleError(err)
+		}
+		if len(pr.Reviewers) < pr.NeededReviewers || len(pr.AwaitingReviewers) > 0 {
+			prs = append(prs, pr)
+		}
+	}
+	return prs, nil
+}
+
+// ListMergedPRs возвращает PR, которые пользователь написал или ревьюил, слитые не раньше since
+func (r *repositoryImpl) ListMergedPRs(ctx context.Context, userID string, since time.Time) ([]domain.MergedPR, error) {
+	q := `
+		SELECT pr.id, pr.title, pr.author_id, pr.status, pr.merged_at
+		FROM pull_requests pr
+		WHERE pr.status = 'MERGED' AND pr.merged_at >= $2
+		  AND (pr.author_id = $1 OR EXISTS (SELECT 1 FROM pr_reviewers prr WHERE prr.pr_id = pr.id AND prr.user_id = $1))
+		ORDER BY pr.merged_at DESC, pr.id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, userID, since)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	prs := make([]domain.MergedPR, 0)
+	for rows.Next() {
+		var pr domain.MergedPR
+		if err := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.MergedAt); err != nil {
+			return nil, r.handleError(err)
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// ListDigestNotifications блокирует неотправленные уведомления пользователя, которые ждут его сводки
+func (r *repositoryImpl) ListDigestNotifications(ctx context.Context, userID string) ([]domain.Notification, error) {
+	q := `
+		SELECT id, user_id, kind, COALESCE(pr_id, ''), text, created_at
+		FROM notifications
+		WHERE user_id = $1 AND sent_at IS NULL
+		ORDER BY id
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, userID)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	notifications := make([]domain.Notification, 0)
+	for rows.Next() {
+		var n domain.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.PullRequestID, &n.Text, &n.CreatedAt); err != nil {
+			return nil, r.handleError(err)
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+// ListDigestRecipients блокирует настройки пользователей в режиме сводки, пропуская
+// заблокированные другими экземплярами
+func (r *repositoryImpl) ListDigestRecipients(ctx context.Context) ([]domain.DigestRecipient, error) {
+	q := `
+		SELECT ` + preferenceColumns + `, digest_sent_at
+		FROM notification_preferences
+		WHERE delivery = $1
+		ORDER BY user_id
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, string(domain.NotificationDeliveryDigest))
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	recipients := make([]domain.DigestRecipient, 0)
+	for rows.Next() {
+		var rec domain.DigestRecipient
+		var quietStart, quietEnd *int
+		p := &rec.Preferences
+		if err := rows.Scan(&p.UserID, &p.Channel, &p.Address, &p.Delivery, &quietStart, &quietEnd, &p.Timezone,
+			&rec.LastSentAt); err != nil {
+			return nil, r.handleError(err)
+		}
+		if quietStart != nil && quietEnd != nil {
+			p.QuietHours = &domain.QuietHours{Start: *quietStart, End: *quietEnd}
+		}
+		recipients = append(recipients, rec)
+	}
+	return recipients, nil
+}
+
+func (r *repositoryImpl) MarkDigestSent(ctx context.Context, userID string, at time.Time) error {
+	_, err := r.getQuerier(ctx).Exec(ctx, `UPDATE notification_preferences SET digest_sent_at = $2 WHERE user_id = $1`, userID, at)
+	return r.handleError(err)
+}
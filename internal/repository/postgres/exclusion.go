@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+
+	"reviewer/internal/domain"
+)
+
+func (r *repositoryImpl) CreateReviewExclusion(ctx context.Context, e domain.ReviewExclusion) (domain.ReviewExclusion, error) {
+	q := `
+		INSERT INTO review_exclusions (reviewer_id, author_id, reason) VALUES ($1, $2, $3)
+		RETURNING id, reviewer_id, author_id, reason, created_at
+	`
+	var out domain.ReviewExclusion
+	err := r.getQuerier(ctx).QueryRow(ctx, q, e.ReviewerID, e.AuthorID, e.Reason).
+		Scan(&out.ID, &out.ReviewerID, &out.AuthorID, &out.Reason, &out.CreatedAt)
+	if err != nil {
+		return domain.ReviewExclusion{}, r.handleError(err)
+	}
+	return out, nil
+}
+
+func (r *repositoryImpl) DeleteReviewExclusion(ctx context.Context, id int64) error {
+	cmdTag, err := r.getQuerier(ctx).Exec(ctx, `DELETE FROM review_exclusions WHERE id = $1`, id)
+	if err != nil {
+		return r.handleError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repositoryImpl) ListReviewExclusions(ctx context.Context, userID string) ([]domain.ReviewExclusion, error) {
+	q := `
+		SELECT id, reviewer_id, author_id, reason, created_at
+		FROM review_exclusions
+		WHERE reviewer_id = $1 OR author_id = $1
+		ORDER BY id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, userID)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	exclusions := make([]domain.ReviewExclusion, 0)
+	for rows.Next() {
+		var e domain.ReviewExclusion
+		if err := rows.Scan(&e.ID, &e.ReviewerID, &e.AuthorID, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, r.handleError(err)
+		}
+		exclusions = append(exclusions, e)
+	}
+	return exclusions, nil
+}
+
+// ListConflictingReviewers возвращает пользователей, которые не могут ревьюить автора:
+// всю цепочку его руководителей и тех, для кого задано правило исключения
+func (r *repositoryImpl) ListConflictingReviewers(ctx context.Context, authorID string) ([]string, error) {
+	q := `
+		WITH RECURSIVE managers AS (
+			SELECT manager_id AS id FROM users WHERE id = $1 AND manager_id IS NOT NULL
+			UNION
+			SELECT u.manager_id FROM users u JOIN managers m ON u.id = m.id WHERE u.manager_id IS NOT NULL
+		)
+		SELECT id FROM managers
+		UNION
+		SELECT reviewer_id FROM review_exclusions WHERE author_id = $1
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, authorID)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, r.handleError(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+
+	"reviewer/internal/domain"
+)
+
+// latencyAggregates считает число замеров и перцентили для столбцов first_review, approval и merge
+const latencyAggregates = `
+	COUNT(first_review), percentile_cont(ARRAY[0.5, 0.9, 0.99]) WITHIN GROUP (ORDER BY first_review),
+	COUNT(approval), percentile_cont(ARRAY[0.5, 0.9, 0.99]) WITHIN GROUP (ORDER BY approval),
+	COUNT(merge), percentile_cont(ARRAY[0.5, 0.9, 0.99]) WITHIN GROUP (ORDER BY merge)`
+
+// GetTeamLatency считает скорость ревью по PR команд, отсчитывая время от создания PR
+func (r *repositoryImpl) GetTeamLatency(ctx context.Context, filter domain.LatencyFilter) ([]domain.LatencyStats, error) {
+	q := `
+		WITH samples AS (
+			SELECT pr.team_name,
+				EXTRACT(EPOCH FROM (
+					SELECT MIN(d.created_at) FROM review_decisions d WHERE d.pr_id = pr.id
+				) - pr.created_at)::float8 / 3600 AS first_review,
+				EXTRACT(EPOCH FROM (
+					SELECT MIN(d.created_at) FROM review_decisions d WHERE d.pr_id = pr.id AND d.decision = 'APPROVED'
+				) - pr.created_at)::float8 / 3600 AS approval,
+				EXTRACT(EPOCH FROM pr.merged_at - pr.created_at)::float8 / 3600 AS merge
+			FROM pull_requests pr
+			WHERE ($1::timestamptz IS NULL OR pr.created_at >= $1)
+			  AND ($2::timestamptz IS NULL OR pr.created_at < $2)
+			  AND ($3 = '' OR pr.team_name = $3)
+		)
+		SELECT team_name,` + latencyAggregates + `
+		FROM samples
+		GROUP BY team_name
+		ORDER BY team_name
+	`
+	return r.queryLatency(ctx, q, filter, func(s *domain.LatencyStats) any { return &s.TeamName })
+}
+
+// GetReviewerLatency считает скорость ревью по ревьюерам, отсчитывая время до первого ревью
+// и одобрения от первого назначения ревьюера на PR, а время до слияния — от создания PR
+func (r *repositoryImpl) GetReviewerLatency(ctx context.Context, filter domain.LatencyFilter) ([]domain.LatencyStats, error) {
+	q := `
+		WITH assignments AS (
+			SELECT h.pr_id, h.reviewer_id, MIN(h.assigned_at) AS assigned_at
+			FROM assignment_history h
+			JOIN pull_requests pr ON pr.id = h.pr_id
+			WHERE h.action = 'ASSIGNED'
+			  AND ($1::timestamptz IS NULL OR pr.created_at >= $1)
+			  AND ($2::timestamptz IS NULL OR pr.created_at < $2)
+			  AND ($3 = '' OR pr.team_name = $3)
+			GROUP BY h.pr_id, h.reviewer_id
+		), samples AS (
+			SELECT a.reviewer_id,
+				EXTRACT(EPOCH FROM (
+					SELECT MIN(d.created_at) FROM review_decisions d
+					WHERE d.pr_id = a.pr_id AND d.reviewer_id = a.reviewer_id
+				) - a.assigned_at)::float8 / 3600 AS first_review,
+				EXTRACT(EPOCH FROM (
+					SELECT MIN(d.created_at) FROM review_decisions d
+					WHERE d.pr_id = a.pr_id AND d.reviewer_id = a.reviewer_id AND d.decision = 'APPROVED'
+				) - a.assigned_at)::float8 / 3600 AS approval,
+				EXTRACT(EPOCH FROM pr.merged_at - pr.created_at)::float8 / 3600 AS merge
+			FROM assignments a
+			JOIN pull_requests pr ON pr.id = a.pr_id
+		)
+		SELECT reviewer_id,` + latencyAggregates + `
+		FROM samples
+		GROUP BY reviewer_id
+		ORDER BY reviewer_id
+	`
+	return r.queryLatency(ctx, q, filter, func(s *domain.LatencyStats) any { return &s.UserID })
+}
+
+// queryLatency выполняет запрос, возвращающий ключ группы и latencyAggregates; key указывает, куда читать ключ
+func (r *repositoryImpl) queryLatency(ctx context.Context, q string, filter domain.LatencyFilter, key func(*domain.LatencyStats) any) ([]domain.LatencyStats, error) {
+	rows, err := r.getQuerier(ctx).Query(ctx, q, filter.From, filter.To, filter.TeamName)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	stats := make([]domain.LatencyStats, 0)
+	for rows.Next() {
+		var s domain.LatencyStats
+		var firstReview, approval, merge []float64
+		if err := rows.Scan(key(&s),
+			&s.TimeToFirstReview.Count, &firstReview,
+			&s.TimeToApproval.Count, &approval,
+			&s.TimeToMerge.Count, &merge); err != nil {
+			return nil, r.handleError(err)
+		}
+		setPercentiles(&s.TimeToFirstReview, firstReview)
+		setPercentiles(&s.TimeToApproval, approval)
+		setPercentiles(&s.TimeToMerge, merge)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// setPercentiles раскладывает результат percentile_cont; без замеров он равен NULL
+func setPercentiles(p *domain.LatencyPercentiles, values []float64) {
+	if len(values) != 3 {
+		return
+	}
+	p.P50, p.P90, p.P99 = &values[0], &values[1], &values[2]
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"reviewer/internal/domain"
+)
+
+const preferenceColumns = `user_id, channel, address, delivery, quiet_start, quiet_end, timezone`
+
+func scanPreferences(row rowScanner) (domain.NotificationPreferences, error) {
+	var p domain.NotificationPreferences
+	var quietStart, quietEnd *int
+	if err := row.Scan(&p.UserID, &p.Channel, &p.Address, &p.Delivery, &quietStart, &quietEnd, &p.Timezone); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+	if quietStart != nil && quietEnd != nil {
+		p.QuietHours = &domain.QuietHours{Start: *quietStart, End: *quietEnd}
+	}
+	return p, nil
+}
+
+func (r *repositoryImpl) GetNotificationPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	q := `SELECT ` + preferenceColumns + ` FROM notification_preferences WHERE user_id = $1`
+	p, err := scanPreferences(r.getQuerier(ctx).QueryRow(ctx, q, userID))
+	return p, r.handleError(err)
+}
+
+func (r *repositoryImpl) SetNotificationPreferences(ctx context.Context, p domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	q := `
+		INSERT INTO notification_preferences (` + preferenceColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE
+		SET channel = EXCLUDED.channel, address = EXCLUDED.address, delivery = EXCLUDED.delivery,
+			quiet_start = EXCLUDED.quiet_start, quiet_end = EXCLUDED.quiet_end, timezone = EXCLUDED.timezone
+		RETURNING ` + preferenceColumns
+	var quietStart, quietEnd *int
+	if p.QuietHours != nil {
+		quietStart, quietEnd = &p.QuietHours.Start, &p.QuietHours.End
+	}
+	saved, err := scanPreferences(r.getQuerier(ctx).QueryRow(ctx, q,
+		p.UserID, string(p.Channel), p.Address, string(p.Delivery), quietStart, quietEnd, p.Timezone))
+	return saved, r.handleError(err)
+}
+
+func (r *repositoryImpl) EnqueueNotifications(ctx context.Context, notifications []domain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	q := `INSERT INTO notifications (user_id, kind, pr_id, text) VALUES ($1, $2, NULLIF($3, ''), $4)`
+	b := &pgx.Batch{}
+	for _, n := range notifications {
+		b.Queue(q, n.UserID, string(n.Kind), n.PullRequestID, n.Text)
+	}
+	br := r.getQuerier(ctx).SendBatch(ctx, b)
+	defer br.Close()
+	for range notifications {
+		if _, err := br.Exec(); err != nil {
+			return r.handleError(err)
+		}
+	}
+	return nil
+}
+
+// ListPendingNotifications блокирует неотправленные уведомления получателей без режима сводки,
+// у которых было меньше maxAttempts неудачных попыток, пропуская заблокированные другими
+// экземплярами. Настройки получателя подставляются по умолчанию, если он их не задавал
+func (r *repositoryImpl) ListPendingNotifications(ctx context.Context, maxAttempts int) ([]domain.PendingNotification, error) {
+	q := `
+		SELECT n.id, n.user_id, n.kind, COALESCE(n.pr_id, ''), n.text, n.created_at,
+			COALESCE(p.channel, $2), COALESCE(p.address, ''), COALESCE(p.delivery, $3),
+			p.quiet_start, p.quiet_end, COALESCE(p.timezone, 'UTC')
+		FROM notifications n
+		LEFT JOIN notification_preferences p ON p.user_id = n.user_id
+		WHERE n.sent_at IS NULL AND n.attempts < $1 AND COALESCE(p.delivery, $3) = $3
+		ORDER BY n.id
+		FOR UPDATE OF n SKIP LOCKED
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, maxAttempts,
+		string(domain.NotificationChannelLog), string(domain.NotificationDeliveryImmediate))
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	pending := make([]domain.PendingNotification, 0)
+	for rows.Next() {
+		var n domain.PendingNotification
+		var quietStart, quietEnd *int
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.PullRequestID, &n.Text, &n.CreatedAt,
+			&n.Preferences.Channel, &n.Preferences.Address, &n.Preferences.Delivery,
+			&quietStart, &quietEnd, &n.Preferences.Timezone); err != nil {
+			return nil, r.handleError(err)
+		}
+		n.Preferences.UserID = n.UserID
+		if quietStart != nil && quietEnd != nil {
+			n.Preferences.QuietHours = &domain.QuietHours{Start: *quietStart, End: *quietEnd}
+		}
+		pending = append(pending, n)
+	}
+	return pending, nil
+}
+
+func (r *repositoryImpl) MarkNotificationsSent(ctx context.Context, ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.getQuerier(ctx).Exec(ctx, `UPDATE notifications SET sent_at = $2 WHERE id = ANY($1)`, ids, at)
+	return r.handleError(err)
+}
+
+func (r *repositoryImpl) RecordNotificationFailure(ctx context.Context, id int64, reason string) error {
+	q := `UPDATE notifications SET attempts = attempts + 1, last_error = $2 WHERE id = $1`
+	_, err := r.getQuerier(ctx).Exec(ctx, q, id, reason)
+	return r.handleError(err)
+}
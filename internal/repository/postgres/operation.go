@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"reviewer/internal/domain"
+)
+
+func (r *repositoryImpl) CreateOperation(ctx context.Context, op *domain.Operation) (*domain.Operation, error) {
+	q := `INSERT INTO operations (kind, team_name, user_ids) VALUES ($1, $2, $3) RETURNING id, created_at`
+	err := r.getQuerier(ctx).QueryRow(ctx, q, op.Kind, op.TeamName, op.UserIDs).Scan(&op.ID, &op.CreatedAt)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	if len(op.RemovedReviews) == 0 {
+		return op, nil
+	}
+
+	b := &pgx.Batch{}
+	for _, rv := range op.RemovedReviews {
+		b.Queue("INSERT INTO operation_removed_reviews (operation_id, pr_id, user_id) VALUES ($1, $2, $3)", op.ID, rv.PullRequestID, rv.UserID)
+	}
+	br := r.getQuerier(ctx).SendBatch(ctx, b)
+	defer br.Close()
+	for i := 0; i < len(op.RemovedReviews); i++ {
+		if _, err := br.Exec(); err != nil {
+			return nil, r.handleError(err)
+		}
+	}
+	return op, nil
+}
+
+func (r *repositoryImpl) GetOperationForUpdate(ctx context.Context, id int64) (domain.Operation, error) {
+	q := `SELECT id, kind, team_name, user_ids, created_at, undone_at FROM operations WHERE id = $1 FOR UPDATE`
+	return r.getOperationInternal(ctx, q, id)
+}
+
+func (r *repositoryImpl) GetLastPendingOperationForUpdate(ctx context.Context, teamName string, kind domain.OperationKind) (domain.Operation, error) {
+	q := `
+		SELECT id, kind, team_name, user_ids, created_at, undone_at
+		FROM operations
+		WHERE team_name = $1 AND kind = $2 AND undone_at IS NULL
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+	return r.getOperationInternal(ctx, q, teamName, kind)
+}
+
+func (r *repositoryImpl) getOperationInternal(ctx context.Context, q string, args ...any) (domain.Operation, error) {
+	var op domain.Operation
+	err := r.getQuerier(ctx).QueryRow(ctx, q, args...).
+		Scan(&op.ID, &op.Kind, &op.TeamName, &op.UserIDs, &op.CreatedAt, &op.UndoneAt)
+	if err != nil {
+		return domain.Operation{}, r.handleError(err)
+	}
+
+	reviewsQ := `SELECT pr_id, user_id FROM operation_removed_reviews WHERE operation_id = $1 ORDER BY pr_id, user_id`
+	rows, err := r.getQuerier(ctx).Query(ctx, reviewsQ, op.ID)
+	if err != nil {
+		return domain.Operation{}, r.handleError(err)
+	}
+	defer rows.Close()
+
+	op.RemovedReviews = make([]domain.ReviewAssignment, 0)
+	for rows.Next() {
+		var rv domain.ReviewAssignment
+		if err := rows.Scan(&rv.PullRequestID, &rv.UserID); err != nil {
+			return domain.Operation{}, r.handleError(err)
+		}
+		op.RemovedReviews = append(op.RemovedReviews, rv)
+	}
+	if op.UserIDs == nil {
+		op.UserIDs = []string{}
+	}
+	return op, nil
+}
+
+func (r *repositoryImpl) RestoreRemovedReviews(ctx context.Context, op domain.Operation) ([]domain.ReviewAssignment, error) {
+	q := `
+		INSERT INTO pr_reviewers (pr_id, user_id)
+		SELECT orr.pr_id, orr.user_id
+		FROM operation_removed_reviews orr
+		JOIN pull_requests pr ON pr.id = orr.pr_id
+		WHERE orr.operation_id = $1
+		  AND pr.status = 'OPEN'
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM pr_reviewers prr
+			WHERE prr.pr_id = orr.pr_id AND prr.created_at >= $2
+		  )
+		ON CONFLICT (pr_id, user_id) DO NOTHING
+		RETURNING pr_id, user_id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, op.ID, op.CreatedAt)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	restored := make([]domain.ReviewAssignment, 0)
+	for rows.Next() {
+		var rv domain.ReviewAssignment
+		if err := rows.Scan(&rv.PullRequestID, &rv.UserID); err != nil {
+			return nil, r.handleError(err)
+		}
+		restored = append(restored, rv)
+	}
+	return restored, nil
+}
+
+func (r *repositoryImpl) MarkOperationUndone(ctx context.Context, id int64) (time.Time, error) {
+	q := `UPDATE operations SET undone_at = NOW() WHERE id = $1 AND undone_at IS NULL RETURNING undone_at`
+	var t time.Time
+	err := r.getQuerier(ctx).QueryRow(ctx, q, id).Scan(&t)
+	if err != nil {
+		return time.Time{}, r.handleError(err)
+	}
+	return t, nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestRepository_Operation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+
+	tName := "op-repo"
+	_, err = repo.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	_, err = repo.CreateUser(ctx, domain.User{ID: "op_a", Username: "A", TeamName: tName, IsActive: true})
+	require.NoError(t, err)
+	_, err = repo.CreateUser(ctx, domain.User{ID: "op_r1", Username: "R1", TeamName: tName, IsActive: true})
+	require.NoError(t, err)
+	_, err = repo.CreateUser(ctx, domain.User{ID: "op_r2", Username: "R2", TeamName: tName, IsActive: true})
+	require.NoError(t, err)
+
+	t.Run("CreateAndGetOperation", func(t *testing.T) {
+		_, err := repo.CreatePR(ctx, &domain.PullRequest{ID: "op-pr-1", Title: "T", AuthorID: "op_a", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+
+		created, err := repo.CreateOperation(ctx, &domain.Operation{
+			Kind:           domain.OperationTeamDeactivation,
+			TeamName:       tName,
+			UserIDs:        []string{"op_r1"},
+			RemovedReviews: []domain.ReviewAssignment{{PullRequestID: "op-pr-1", UserID: "op_r1"}},
+		})
+		require.NoError(t, err)
+
+		op, err := repo.GetOperationForUpdate(ctx, created.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.OperationTeamDeactivation, op.Kind)
+		assert.Equal(t, []string{"op_r1"}, op.UserIDs)
+		require.Len(t, op.RemovedReviews, 1)
+		assert.Equal(t, "op-pr-1", op.RemovedReviews[0].PullRequestID)
+		assert.Nil(t, op.UndoneAt)
+	})
+
+	t.Run("GetOperation_NotFound", func(t *testing.T) {
+		_, err := repo.GetOperationForUpdate(ctx, 100500)
+
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("RestoreRemovedReviews_SkipsMergedAndReassigned", func(t *testing.T) {
+		_, err := repo.CreatePR(ctx, &domain.PullRequest{ID: "op-open", Title: "O", AuthorID: "op_a", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "op-merged", Title: "M", AuthorID: "op_a", TeamName: tName, Status: domain.PRStatusMerged})
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "op-reassigned", Title: "R", AuthorID: "op_a", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		op, err := repo.CreateOperation(ctx, &domain.Operation{
+			Kind:     domain.OperationTeamDeactivation,
+			TeamName: tName,
+			UserIDs:  []string{"op_r1"},
+			RemovedReviews: []domain.ReviewAssignment{
+				{PullRequestID: "op-open", UserID: "op_r1"},
+				{PullRequestID: "op-merged", UserID: "op_r1"},
+				{PullRequestID: "op-reassigned", UserID: "op_r1"},
+			},
+		})
+		require.NoError(t, err)
+		err = repo.AddReviewers(ctx, "op-reassigned", []string{"op_r2"})
+		require.NoError(t, err)
+
+		restored, err := repo.RestoreRemovedReviews(ctx, *op)
+
+		require.NoError(t, err)
+		require.Len(t, restored, 1)
+		assert.Equal(t, domain.ReviewAssignment{PullRequestID: "op-open", UserID: "op_r1"}, restored[0])
+	})
+
+	t.Run("MarkOperationUndone_Twice", func(t *testing.T) {
+		op, err := repo.CreateOperation(ctx, &domain.Operation{Kind: domain.OperationTeamDeactivation, TeamName: tName, UserIDs: []string{}})
+		require.NoError(t, err)
+
+		_, err = repo.MarkOperationUndone(ctx, op.ID)
+		require.NoError(t, err)
+		_, err = repo.MarkOperationUndone(ctx, op.ID)
+
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("GetLastPendingOperation", func(t *testing.T) {
+		isolatedTeam := "op-last"
+		_, err := repo.CreateTeam(ctx, isolatedTeam)
+		require.NoError(t, err)
+		first, err := repo.CreateOperation(ctx, &domain.Operation{Kind: domain.OperationTeamDeactivation, TeamName: isolatedTeam, UserIDs: []string{}})
+		require.NoError(t, err)
+		second, err := repo.CreateOperation(ctx, &domain.Operation{Kind: domain.OperationTeamDeactivation, TeamName: isolatedTeam, UserIDs: []string{}})
+		require.NoError(t, err)
+		_, err = repo.MarkOperationUndone(ctx, second.ID)
+		require.NoError(t, err)
+
+		op, err := repo.GetLastPendingOperationForUpdate(ctx, isolatedTeam, domain.OperationTeamDeactivation)
+
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, op.ID)
+	})
+}
@@ -59,6 +59,14 @@ func (r *repositoryImpl) RunInTx(ctx context.Context, fn func(ctx context.Contex
 	return tx.Commit(ctx)
 }
 
+// TryAdvisoryLock пытается взять advisory-блокировку key до конца текущей транзакции.
+// Вне транзакции блокировка снимается сразу после запроса
+func (r *repositoryImpl) TryAdvisoryLock(ctx context.Context, key int64) (bool, error) {
+	var acquired bool
+	err := r.getQuerier(ctx).QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, key).Scan(&acquired)
+	return acquired, r.handleError(err)
+}
+
 func (r *repositoryImpl) getQuerier(ctx context.Context) querier {
 	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
 		return tx
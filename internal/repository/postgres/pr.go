@@ -10,13 +10,31 @@ import (
 )
 
 func (r *repositoryImpl) CreatePR(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
-	q := `INSERT INTO pull_requests (id, title, author_id, team_name, status) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
-	err := r.getQuerier(ctx).QueryRow(ctx, q, pr.ID, pr.Title, pr.AuthorID, pr.TeamName, pr.Status).
+	if pr.NeededReviewers == 0 {
+		pr.NeededReviewers = domain.DefaultNeededReviewers
+	}
+	if pr.Labels == nil {
+		pr.Labels = []string{}
+	}
+	if pr.ChangedPaths == nil {
+		pr.ChangedPaths = []string{}
+	}
+	if pr.ExcludedReviewers == nil {
+		pr.ExcludedReviewers = []string{}
+	}
+	q := `
+		INSERT INTO pull_requests (id, title, author_id, team_name, status, needed_reviewers, labels, changed_paths, repository, excluded_reviewers)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10)
+		RETURNING id, created_at
+	`
+	err := r.getQuerier(ctx).QueryRow(ctx, q, pr.ID, pr.Title, pr.AuthorID, pr.TeamName, pr.Status, pr.NeededReviewers,
+		pr.Labels, pr.ChangedPaths, pr.Repository, pr.ExcludedReviewers).
 		Scan(&pr.ID, &pr.CreatedAt)
 	if err != nil {
 		return nil, r.handleError(err)
 	}
 	pr.Reviewers = []string{}
+	pr.ShadowReviewers = []string{}
 	return pr, nil
 }
 
@@ -29,13 +47,17 @@ func (r *repositoryImpl) GetPRForUpdate(ctx context.Context, id string) (domain.
 }
 
 func (r *repositoryImpl) getPRInternal(ctx context.Context, id string, forUpdate bool) (domain.PullRequest, error) {
-	q := `SELECT id, title, author_id, team_name, status, created_at, merged_at FROM pull_requests WHERE id = $1`
+	q := `
+		SELECT id, title, author_id, team_name, status, created_at, merged_at,
+		       needed_reviewers, labels, changed_paths, COALESCE(repository, ''), excluded_reviewers
+		FROM pull_requests
+		WHERE id = $1`
 	if forUpdate {
 		q += ` FOR UPDATE`
 	}
 	var pr domain.PullRequest
 	err := r.getQuerier(ctx).QueryRow(ctx, q, id).
-		Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.TeamName, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
+		Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.TeamName, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.NeededReviewers, &pr.Labels, &pr.ChangedPaths, &pr.Repository, &pr.ExcludedReviewers)
 	if err != nil {
 		return domain.PullRequest{}, r.handleError(err)
 	}
@@ -45,9 +67,30 @@ func (r *repositoryImpl) getPRInternal(ctx context.Context, id string, forUpdate
 		return domain.PullRequest{}, err
 	}
 	pr.Reviewers = reviewers
+
+	pr.ShadowReviewers, err = r.GetShadowReviewerIDs(ctx, id)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
 	return pr, nil
 }
 
+func (r *repositoryImpl) AddPRExclusions(ctx context.Context, prID string, userIDs []string) error {
+	q := `
+		UPDATE pull_requests
+		SET excluded_reviewers = ARRAY(SELECT DISTINCT unnest(excluded_reviewers || $2::TEXT[]) ORDER BY 1)
+		WHERE id = $1
+	`
+	cmdTag, err := r.getQuerier(ctx).Exec(ctx, q, prID, userIDs)
+	if err != nil {
+		return r.handleError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
 func (r *repositoryImpl) UpdatePRStatus(ctx context.Context, id string, status domain.PRStatus) (time.Time, error) {
 	q := `UPDATE pull_requests 
 	      SET status = $1, merged_at = CASE WHEN $1 = 'MERGED' THEN NOW() ELSE NULL END 
@@ -62,17 +105,81 @@ func (r *repositoryImpl) UpdatePRStatus(ctx context.Context, id string, status d
 	return t, nil
 }
 
+func (r *repositoryImpl) ListUnderstaffedPRs(ctx context.Context, teamName string) ([]domain.UnderstaffedPR, error) {
+	q := `
+		SELECT pr.id, pr.title, pr.author_id, pr.status, pr.team_name, pr.needed_reviewers,
+		       COALESCE(array_agg(prr.user_id ORDER BY prr.user_id) FILTER (WHERE prr.user_id IS NOT NULL), '{}')
+		FROM pull_requests pr
+		LEFT JOIN pr_reviewers prr ON prr.pr_id = pr.id
+		WHERE pr.status = 'OPEN' AND ($1 = '' OR pr.team_name = $1)
+		GROUP BY pr.id
+		HAVING COUNT(prr.user_id) < pr.needed_reviewers
+		ORDER BY pr.created_at, pr.id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, teamName)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	prs := make([]domain.UnderstaffedPR, 0)
+	for rows.Next() {
+		var pr domain.UnderstaffedPR
+		if err := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.TeamName, &pr.NeededReviewers, &pr.Reviewers); err != nil {
+			return nil, r.handleError(err)
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
 func (r *repositoryImpl) AddReviewers(ctx context.Context, prID string, reviewerIDs []string) error {
-	if len(reviewerIDs) == 0 {
+	matches := make([]domain.ReviewerMatch, len(reviewerIDs))
+	for i, id := range reviewerIDs {
+		matches[i] = domain.ReviewerMatch{UserID: id}
+	}
+	return r.AddMatchedReviewers(ctx, prID, matches)
+}
+
+func (r *repositoryImpl) AddMatchedReviewers(ctx context.Context, prID string, matches []domain.ReviewerMatch) error {
+	if len(matches) == 0 {
 		return nil
 	}
+	q := `
+		WITH ins AS (
+			INSERT INTO pr_reviewers (pr_id, user_id, match_reason, matched_tags, rule_team, rule_line, rule_pattern, matched_path,
+				strategy, pool_size, open_reviews, rng_seed)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING pr_id, user_id, created_at, match_reason, strategy, pool_size, open_reviews, rng_seed
+		)
+		INSERT INTO assignment_history (pr_id, author_id, reviewer_id, assigned_at, match_reason, strategy, pool_size, open_reviews, rng_seed)
+		SELECT ins.pr_id, p.author_id, ins.user_id, ins.created_at, ins.match_reason, ins.strategy, ins.pool_size, ins.open_reviews, ins.rng_seed
+		FROM ins
+		JOIN pull_requests p ON p.id = ins.pr_id
+	`
 	b := &pgx.Batch{}
-	for _, userID := range reviewerIDs {
-		b.Queue("INSERT INTO pr_reviewers (pr_id, user_id) VALUES ($1, $2)", prID, userID)
+	for _, m := range matches {
+		tags := m.MatchedTags
+		if tags == nil {
+			tags = []string{}
+		}
+		var ruleTeam, rulePattern, path *string
+		var ruleLine *int
+		if m.Rule != nil {
+			ruleTeam, ruleLine, rulePattern, path = &m.Rule.TeamName, &m.Rule.Line, &m.Rule.Pattern, &m.Rule.Path
+		}
+		var strategy *string
+		var poolSize, openReviews *int
+		var seed *int64
+		if e := m.Explanation; e != nil {
+			s := string(e.Strategy)
+			strategy, poolSize, openReviews, seed = &s, &e.PoolSize, &e.OpenReviews, &e.Seed
+		}
+		b.Queue(q, prID, m.UserID, string(m.Reason), tags, ruleTeam, ruleLine, rulePattern, path, strategy, poolSize, openReviews, seed)
 	}
 	br := r.getQuerier(ctx).SendBatch(ctx, b)
 	defer br.Close()
-	for i := 0; i < len(reviewerIDs); i++ {
+	for i := 0; i < len(matches); i++ {
 		_, err := br.Exec()
 		if err != nil {
 			return r.handleError(err)
@@ -81,6 +188,96 @@ func (r *repositoryImpl) AddReviewers(ctx context.Context, prID string, reviewer
 	return nil
 }
 
+// AddManualReviewer добавляет ревьюера, выбранного вручную, и записывает в историю, кто его добавил.
+// Пустой actorID означает, что действующий пользователь неизвестен
+func (r *repositoryImpl) AddManualReviewer(ctx context.Context, prID, userID, actorID string) error {
+	q := `
+		WITH ins AS (
+			INSERT INTO pr_reviewers (pr_id, user_id, match_reason, matched_tags)
+			VALUES ($1, $2, $3, '{}')
+			RETURNING pr_id, user_id, created_at, match_reason
+		)
+		INSERT INTO assignment_history (pr_id, author_id, reviewer_id, assigned_at, match_reason, actor_id)
+		SELECT ins.pr_id, p.author_id, ins.user_id, ins.created_at, ins.match_reason, NULLIF($4, '')
+		FROM ins
+		JOIN pull_requests p ON p.id = ins.pr_id
+	`
+	_, err := r.getQuerier(ctx).Exec(ctx, q, prID, userID, string(domain.MatchReasonManual), actorID)
+	return r.handleError(err)
+}
+
+// RecordReviewerRemoval записывает в историю ручное снятие ревьюера с PR
+func (r *repositoryImpl) RecordReviewerRemoval(ctx context.Context, prID, userID, actorID string) error {
+	q := `
+		INSERT INTO assignment_history (pr_id, author_id, reviewer_id, action, actor_id)
+		SELECT id, author_id, $2, $3, $4
+		FROM pull_requests
+		WHERE id = $1
+	`
+	cmdTag, err := r.getQuerier(ctx).Exec(ctx, q, prID, userID, string(domain.AssignmentActionRemoved), actorID)
+	if err != nil {
+		return r.handleError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// RecordDecline записывает в историю отказ ревьюера от ревью PR
+func (r *repositoryImpl) RecordDecline(ctx context.Context, prID, userID string, reason domain.DeclineReason) error {
+	q := `
+		INSERT INTO assignment_history (pr_id, author_id, reviewer_id, action, actor_id, decline_reason)
+		SELECT id, author_id, $2, $3, $2, $4
+		FROM pull_requests
+		WHERE id = $1
+	`
+	cmdTag, err := r.getQuerier(ctx).Exec(ctx, q, prID, userID, string(domain.AssignmentActionDeclined), string(reason))
+	if err != nil {
+		return r.handleError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repositoryImpl) GetReviewerMatches(ctx context.Context, prID string) ([]domain.ReviewerMatch, error) {
+	q := `
+		SELECT user_id, COALESCE(match_reason, ''), matched_tags, rule_team, rule_line, rule_pattern, matched_path,
+			strategy, pool_size, open_reviews, rng_seed
+		FROM pr_reviewers
+		WHERE pr_id = $1
+		ORDER BY user_id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, prID)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	matches := make([]domain.ReviewerMatch, 0)
+	for rows.Next() {
+		var m domain.ReviewerMatch
+		var ruleTeam, rulePattern, path *string
+		var ruleLine *int
+		var e explanationColumns
+		if err := rows.Scan(&m.UserID, &m.Reason, &m.MatchedTags, &ruleTeam, &ruleLine, &rulePattern, &path,
+			&e.strategy, &e.poolSize, &e.openReviews, &e.seed); err != nil {
+			return nil, r.handleError(err)
+		}
+		m.Explanation = e.explanation()
+		if ruleTeam != nil {
+			m.Rule = &domain.OwnershipRule{TeamName: *ruleTeam, Pattern: *rulePattern, Path: *path}
+			if ruleLine != nil {
+				m.Rule.Line = *ruleLine
+			}
+		}
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
 func (r *repositoryImpl) RemoveReviewer(ctx context.Context, prID, userID string) error {
 	q := `DELETE FROM pr_reviewers WHERE pr_id = $1 AND user_id = $2`
 	cmdTag, err := r.getQuerier(ctx).Exec(ctx, q, prID, userID)
@@ -114,9 +311,33 @@ func (r *repositoryImpl) GetReviewerIDs(ctx context.Context, prID string) ([]str
 	return ids, nil
 }
 
+func (r *repositoryImpl) AddShadowReviewer(ctx context.Context, prID, userID string) error {
+	q := `INSERT INTO pr_shadow_reviewers (pr_id, user_id) VALUES ($1, $2)`
+	_, err := r.getQuerier(ctx).Exec(ctx, q, prID, userID)
+	return r.handleError(err)
+}
+
+func (r *repositoryImpl) GetShadowReviewerIDs(ctx context.Context, prID string) ([]string, error) {
+	q := `SELECT user_id FROM pr_shadow_reviewers WHERE pr_id = $1 ORDER BY user_id`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, prID)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, r.handleError(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 func (r *repositoryImpl) ListPRsByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequestShort, error) {
 	q := `
-		SELECT pr.id, pr.title, pr.author_id, pr.status
+		SELECT pr.id, pr.title, pr.author_id, pr.status, r.created_at
 		FROM pull_requests pr
 		JOIN pr_reviewers r ON pr.id = r.pr_id
 		WHERE r.user_id = $1
@@ -131,7 +352,7 @@ func (r *repositoryImpl) ListPRsByReviewer(ctx context.Context, reviewerID strin
 	var prs []domain.PullRequestShort
 	for rows.Next() {
 		var pr domain.PullRequestShort
-		if err := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status); err != nil {
+		if err := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.AssignedAt); err != nil {
 			return nil, r.handleError(err)
 		}
 		prs = append(prs, pr)
@@ -177,3 +398,52 @@ func (r *repositoryImpl) RemoveReviewersFromOpenPRs(ctx context.Context, userIDs
 	}
 	return affectedPRs, nil
 }
+
+func (r *repositoryImpl) ListOpenReviewAssignments(ctx context.Context, userIDs []string) ([]domain.ReviewAssignment, error) {
+	q := `
+		SELECT prr.pr_id, prr.user_id
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.user_id = ANY($1) AND pr.status = 'OPEN'
+		ORDER BY prr.pr_id, prr.user_id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, userIDs)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	assignments := make([]domain.ReviewAssignment, 0)
+	for rows.Next() {
+		var a domain.ReviewAssignment
+		if err := rows.Scan(&a.PullRequestID, &a.UserID); err != nil {
+			return nil, r.handleError(err)
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, nil
+}
+
+// explanationColumns — столбцы объяснения назначения; все пусты для назначений, сделанных до их появления
+type explanationColumns struct {
+	strategy              *string
+	poolSize, openReviews *int
+	seed                  *int64
+}
+
+func (c explanationColumns) explanation() *domain.AssignmentExplanation {
+	if c.strategy == nil {
+		return nil
+	}
+	e := &domain.AssignmentExplanation{Strategy: domain.AssignmentStrategy(*c.strategy)}
+	if c.poolSize != nil {
+		e.PoolSize = *c.poolSize
+	}
+	if c.openReviews != nil {
+		e.OpenReviews = *c.openReviews
+	}
+	if c.seed != nil {
+		e.Seed = *c.seed
+	}
+	return e
+}
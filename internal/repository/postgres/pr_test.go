@@ -127,4 +127,57 @@ func TestRepository_PR(t *testing.T) {
 		prClosed, _ := repo.GetPR(ctx, "pr-closed")
 		assert.NotEmpty(t, prClosed.Reviewers)
 	})
+	t.Run("ListUnderstaffedPRs", func(t *testing.T) {
+		isolatedTeam := "understaffed-repo"
+		_, err := repo.CreateTeam(ctx, isolatedTeam)
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "us_a", Username: "A", TeamName: isolatedTeam, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "us_r1", Username: "R1", TeamName: isolatedTeam, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "us_r2", Username: "R2", TeamName: isolatedTeam, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "us-full", Title: "F", AuthorID: "us_a", TeamName: isolatedTeam, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		err = repo.AddReviewers(ctx, "us-full", []string{"us_r1", "us_r2"})
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "us-half", Title: "H", AuthorID: "us_a", TeamName: isolatedTeam, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		err = repo.AddReviewers(ctx, "us-half", []string{"us_r1"})
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "us-merged", Title: "M", AuthorID: "us_a", TeamName: isolatedTeam, Status: domain.PRStatusMerged})
+		require.NoError(t, err)
+
+		prs, err := repo.ListUnderstaffedPRs(ctx, isolatedTeam)
+
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		assert.Equal(t, "us-half", prs[0].ID)
+		assert.Equal(t, domain.DefaultNeededReviewers, prs[0].NeededReviewers)
+		assert.Equal(t, []string{"us_r1"}, prs[0].Reviewers)
+	})
+
+	t.Run("AddMatchedReviewers_RoundTrip", func(t *testing.T) {
+		prID := "pr-matches"
+		_, err := repo.CreatePR(ctx, &domain.PullRequest{
+			ID: prID, Title: "T", AuthorID: "auth", TeamName: tName, Status: domain.PRStatusOpen,
+			ChangedPaths: []string{"db/schema.sql"},
+		})
+		require.NoError(t, err)
+		rule := &domain.OwnershipRule{TeamName: tName, Line: 3, Pattern: "/db/", Path: "db/schema.sql"}
+		in := []domain.ReviewerMatch{
+			{UserID: "rev1", Reason: domain.MatchReasonCodeOwner, MatchedTags: []string{}, Rule: rule},
+			{UserID: "rev2", Reason: domain.MatchReasonExpertise, MatchedTags: []string{"db"}},
+		}
+
+		err = repo.AddMatchedReviewers(ctx, prID, in)
+		require.NoError(t, err)
+		got, err := repo.GetReviewerMatches(ctx, prID)
+
+		require.NoError(t, err)
+		assert.Equal(t, in, got)
+		pr, err := repo.GetPR(ctx, prID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"db/schema.sql"}, pr.ChangedPaths)
+	})
 }
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"reviewer/internal/domain"
+)
+
+func (r *repositoryImpl) CreateRepository(ctx context.Context, name string) (domain.Repository, error) {
+	q := `INSERT INTO repositories (name) VALUES ($1) RETURNING name, created_at`
+	var repo domain.Repository
+	err := r.getQuerier(ctx).QueryRow(ctx, q, name).Scan(&repo.Name, &repo.CreatedAt)
+	if err != nil {
+		return domain.Repository{}, r.handleError(err)
+	}
+	repo.OwnerTeams = []string{}
+	return repo, nil
+}
+
+func (r *repositoryImpl) GetRepository(ctx context.Context, name string) (domain.Repository, error) {
+	q := `
+		SELECT r.name, r.created_at,
+		       COALESCE(array_agg(ro.team_name ORDER BY ro.position) FILTER (WHERE ro.team_name IS NOT NULL), '{}')
+		FROM repositories r
+		LEFT JOIN repository_owners ro ON ro.repository_name = r.name
+		WHERE r.name = $1
+		GROUP BY r.name
+	`
+	var repo domain.Repository
+	err := r.getQuerier(ctx).QueryRow(ctx, q, name).Scan(&repo.Name, &repo.CreatedAt, &repo.OwnerTeams)
+	if err != nil {
+		return domain.Repository{}, r.handleError(err)
+	}
+	return repo, nil
+}
+
+// SetRepositoryOwners заменяет команды-владельцы репозитория; первая команда считается основной
+func (r *repositoryImpl) SetRepositoryOwners(ctx context.Context, name string, teamNames []string) error {
+	q := `DELETE FROM repository_owners WHERE repository_name = $1`
+	if _, err := r.getQuerier(ctx).Exec(ctx, q, name); err != nil {
+		return r.handleError(err)
+	}
+
+	b := &pgx.Batch{}
+	for i, team := range teamNames {
+		b.Queue("INSERT INTO repository_owners (repository_name, team_name, position) VALUES ($1, $2, $3)", name, team, i)
+	}
+	br := r.getQuerier(ctx).SendBatch(ctx, b)
+	defer br.Close()
+	for i := 0; i < len(teamNames); i++ {
+		if _, err := br.Exec(); err != nil {
+			return r.handleError(err)
+		}
+	}
+	return nil
+}
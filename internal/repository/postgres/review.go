@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"reviewer/internal/domain"
+)
+
+func (r *repositoryImpl) AddReview(ctx context.Context, review domain.Review) (domain.Review, error) {
+	q := `
+		INSERT INTO review_decisions (pr_id, reviewer_id, decision)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	err := r.getQuerier(ctx).QueryRow(ctx, q, review.PullRequestID, review.ReviewerID, string(review.Decision)).
+		Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		return domain.Review{}, r.handleError(err)
+	}
+	return review, nil
+}
+
+// ListStaleReviews возвращает назначения на открытые PR, по которым ревьюер ничего не решил
+// с момента назначения, а на now истёк срок напоминания или переназначения команды PR
+func (r *repositoryImpl) ListStaleReviews(ctx context.Context, now time.Time) ([]domain.StaleReview, error) {
+	q := `
+		SELECT prr.pr_id, prr.user_id, t.name, prr.created_at, prr.reminded_at, prr.escalation_failed_at, t.reminder_hours, t.escalation_hours
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		JOIN teams t ON t.name = pr.team_name
+		WHERE pr.status = 'OPEN'
+		  AND prr.created_at <= $1 - make_interval(hours => LEAST(t.reminder_hours, t.escalation_hours))
+		  AND NOT EXISTS (
+		      SELECT 1 FROM review_decisions d
+		      WHERE d.pr_id = prr.pr_id AND d.reviewer_id = prr.user_id AND d.created_at >= prr.created_at)
+		ORDER BY prr.created_at, prr.pr_id, prr.user_id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, now)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	stale := make([]domain.StaleReview, 0)
+	for rows.Next() {
+		var s domain.StaleReview
+		if err := rows.Scan(&s.PullRequestID, &s.ReviewerID, &s.TeamName, &s.AssignedAt, &s.RemindedAt, &s.EscalationFailedAt,
+			&s.SLA.ReminderHours, &s.SLA.EscalationHours); err != nil {
+			return nil, r.handleError(err)
+		}
+		stale = append(stale, s)
+	}
+	return stale, nil
+}
+
+func (r *repositoryImpl) MarkReminded(ctx context.Context, prID, userID string, at time.Time) error {
+	q := `UPDATE pr_reviewers SET reminded_at = $3 WHERE pr_id = $1 AND user_id = $2`
+	cmdTag, err := r.getQuerier(ctx).Exec(ctx, q, prID, userID, at)
+	if err != nil {
+		return r.handleError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// MarkEscalationFailed запоминает неудачную попытку переназначить ревью, чтобы не повторять её
+// при каждой проверке
+func (r *repositoryImpl) MarkEscalationFailed(ctx context.Context, prID, userID string, at time.Time) error {
+	q := `UPDATE pr_reviewers SET escalation_failed_at = $3 WHERE pr_id = $1 AND user_id = $2`
+	cmdTag, err := r.getQuerier(ctx).Exec(ctx, q, prID, userID, at)
+	if err != nil {
+		return r.handleError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+
+	"reviewer/internal/domain"
+)
+
+func (r *repositoryImpl) SaveRuleSet(ctx context.Context, teamName, content string) (domain.RuleSetVersion, error) {
+	q := `
+		INSERT INTO team_rule_sets (team_name, version, content)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2
+		FROM team_rule_sets
+		WHERE team_name = $1
+		RETURNING team_name, version, content, created_at
+	`
+	var v domain.RuleSetVersion
+	err := r.getQuerier(ctx).QueryRow(ctx, q, teamName, content).Scan(&v.TeamName, &v.Version, &v.Content, &v.CreatedAt)
+	if err != nil {
+		return domain.RuleSetVersion{}, r.handleError(err)
+	}
+	return v, nil
+}
+
+// GetRuleSet возвращает версию набора правил команды; version = 0 означает последнюю
+func (r *repositoryImpl) GetRuleSet(ctx context.Context, teamName string, version int) (domain.RuleSetVersion, error) {
+	q := `
+		SELECT team_name, version, content, created_at
+		FROM team_rule_sets
+		WHERE team_name = $1 AND ($2 = 0 OR version = $2)
+		ORDER BY version DESC
+		LIMIT 1
+	`
+	var v domain.RuleSetVersion
+	err := r.getQuerier(ctx).QueryRow(ctx, q, teamName, version).Scan(&v.TeamName, &v.Version, &v.Content, &v.CreatedAt)
+	if err != nil {
+		return domain.RuleSetVersion{}, r.handleError(err)
+	}
+	return v, nil
+}
+
+func (r *repositoryImpl) ListRuleSetVersions(ctx context.Context, teamName string) ([]int, error) {
+	q := `SELECT version FROM team_rule_sets WHERE team_name = $1 ORDER BY version`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, teamName)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	versions := make([]int, 0)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, r.handleError(err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+func (r *repositoryImpl) SaveRuleTrace(ctx context.Context, prID string, trace domain.RuleTrace) error {
+	q := `
+		INSERT INTO pr_rule_traces (pr_id, team_name, version, steps) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (pr_id) DO UPDATE
+		SET team_name = EXCLUDED.team_name, version = EXCLUDED.version, steps = EXCLUDED.steps, evaluated_at = NOW()
+	`
+	_, err := r.getQuerier(ctx).Exec(ctx, q, prID, trace.TeamName, trace.Version, trace.Steps)
+	return r.handleError(err)
+}
+
+func (r *repositoryImpl) GetRuleTrace(ctx context.Context, prID string) (domain.RuleTrace, error) {
+	q := `SELECT team_name, version, steps, evaluated_at FROM pr_rule_traces WHERE pr_id = $1`
+	var t domain.RuleTrace
+	err := r.getQuerier(ctx).QueryRow(ctx, q, prID).Scan(&t.TeamName, &t.Version, &t.Steps, &t.EvaluatedAt)
+	if err != nil {
+		return domain.RuleTrace{}, r.handleError(err)
+	}
+	return t, nil
+}
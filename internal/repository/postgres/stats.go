@@ -2,25 +2,177 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"reviewer/internal/domain"
 )
 
-func (r *repositoryImpl) GetReviewerStats(ctx context.Context) ([]domain.UserAssignmentStats, error) {
-	q := `SELECT user_id, COUNT(*) FROM pr_reviewers GROUP BY user_id ORDER BY user_id`
-	rows, err := r.getQuerier(ctx).Query(ctx, q)
+// GetAssignmentStats считает назначения по истории, группируя их по filter.GroupBy.
+// Неделя начинается в понедельник по UTC
+func (r *repositoryImpl) GetAssignmentStats(ctx context.Context, filter domain.AssignmentStatsFilter) ([]domain.AssignmentStats, error) {
+	var key string
+	switch filter.GroupBy {
+	case domain.StatsGroupByTeam:
+		key = `u.team_name`
+	case domain.StatsGroupByWeek:
+		key = `date_trunc('week', h.assigned_at AT TIME ZONE 'UTC')`
+	default:
+		key = `h.reviewer_id`
+	}
+	q := `
+		SELECT ` + key + `, COUNT(*),
+			COUNT(*) FILTER (WHERE NOT EXISTS (
+				SELECT 1 FROM pr_reviewers prr WHERE prr.pr_id = h.pr_id AND prr.user_id = h.reviewer_id
+			))
+		FROM assignment_history h
+		JOIN users u ON u.id = h.reviewer_id
+		JOIN pull_requests pr ON pr.id = h.pr_id
+		WHERE h.action = 'ASSIGNED'
+		  AND ($1::timestamptz IS NULL OR h.assigned_at >= $1)
+		  AND ($2::timestamptz IS NULL OR h.assigned_at < $2)
+		  AND ($3 = '' OR u.team_name = $3)
+		  AND ($4 = '' OR pr.status = $4)
+		GROUP BY 1
+		ORDER BY 1
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, filter.From, filter.To, filter.TeamName, string(filter.Status))
 	if err != nil {
 		return nil, r.handleError(err)
 	}
 	defer rows.Close()
 
-	stats := make([]domain.UserAssignmentStats, 0)
+	stats := make([]domain.AssignmentStats, 0)
 	for rows.Next() {
-		var stat domain.UserAssignmentStats
-		if err := rows.Scan(&stat.UserID, &stat.AssignmentCount); err != nil {
+		var stat domain.AssignmentStats
+		var dest any
+		switch filter.GroupBy {
+		case domain.StatsGroupByTeam:
+			dest = &stat.TeamName
+		case domain.StatsGroupByWeek:
+			dest = &stat.WeekStart
+		default:
+			dest = &stat.UserID
+		}
+		if err := rows.Scan(dest, &stat.AssignmentCount, &stat.RemovedCount); err != nil {
 			return nil, r.handleError(err)
 		}
 		stats = append(stats, stat)
 	}
 	return stats, nil
 }
+
+func (r *repositoryImpl) ListPairAssignments(ctx context.Context, authorID string, since time.Time) ([]domain.PairAssignment, error) {
+	q := `
+		SELECT reviewer_id, assigned_at
+		FROM assignment_history
+		WHERE author_id = $1 AND assigned_at >= $2 AND action = 'ASSIGNED'
+		ORDER BY assigned_at
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, authorID, since)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.PairAssignment, 0)
+	for rows.Next() {
+		var a domain.PairAssignment
+		if err := rows.Scan(&a.ReviewerID, &a.AssignedAt); err != nil {
+			return nil, r.handleError(err)
+		}
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+func (r *repositoryImpl) ListAssignmentHistory(ctx context.Context, prID string) ([]domain.AssignmentRecord, error) {
+	q := `
+		SELECT reviewer_id, author_id, action, COALESCE(actor_id, ''), COALESCE(decline_reason, ''), COALESCE(match_reason, ''), assigned_at,
+			strategy, pool_size, open_reviews, rng_seed
+		FROM assignment_history
+		WHERE pr_id = $1
+		ORDER BY assigned_at, id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, prID)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.AssignmentRecord, 0)
+	for rows.Next() {
+		var a domain.AssignmentRecord
+		var e explanationColumns
+		if err := rows.Scan(&a.ReviewerID, &a.AuthorID, &a.Action, &a.ActorID, &a.DeclineReason, &a.Reason, &a.AssignedAt, &e.strategy, &e.poolSize, &e.openReviews, &e.seed); err != nil {
+			return nil, r.handleError(err)
+		}
+		a.Explanation = e.explanation()
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+func (r *repositoryImpl) GetPairStats(ctx context.Context, teamName string) ([]domain.PairStats, error) {
+	q := `
+		SELECT h.author_id, h.reviewer_id, COUNT(*), MAX(h.assigned_at)
+		FROM assignment_history h
+		JOIN users a ON a.id = h.author_id
+		WHERE h.action = 'ASSIGNED' AND ($1 = '' OR a.team_name = $1)
+		GROUP BY h.author_id, h.reviewer_id
+		ORDER BY h.author_id, h.reviewer_id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, teamName)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	stats := make([]domain.PairStats, 0)
+	for rows.Next() {
+		var s domain.PairStats
+		if err := rows.Scan(&s.AuthorID, &s.ReviewerID, &s.AssignmentCount, &s.LastAssignedAt); err != nil {
+			return nil, r.handleError(err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetDeclineStats возвращает назначения и отказы каждого ревьюера; teamName ограничивает ревьюеров командой
+func (r *repositoryImpl) GetDeclineStats(ctx context.Context, teamName string) ([]domain.DeclineStats, error) {
+	q := `
+		SELECT h.reviewer_id, u.team_name, h.action, COALESCE(h.decline_reason, ''), COUNT(*)
+		FROM assignment_history h
+		JOIN users u ON u.id = h.reviewer_id
+		WHERE h.action IN ('ASSIGNED', 'DECLINED') AND ($1 = '' OR u.team_name = $1)
+		GROUP BY h.reviewer_id, u.team_name, h.action, h.decline_reason
+		ORDER BY h.reviewer_id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, teamName)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	stats := make([]domain.DeclineStats, 0)
+	for rows.Next() {
+		var userID, team string
+		var action domain.AssignmentAction
+		var reason domain.DeclineReason
+		var count int64
+		if err := rows.Scan(&userID, &team, &action, &reason, &count); err != nil {
+			return nil, r.handleError(err)
+		}
+		if len(stats) == 0 || stats[len(stats)-1].UserID != userID {
+			stats = append(stats, domain.DeclineStats{UserID: userID, TeamName: team, ByReason: map[domain.DeclineReason]int64{}})
+		}
+		s := &stats[len(stats)-1]
+		if action == domain.AssignmentActionDeclined {
+			s.Declines += count
+			s.ByReason[reason] += count
+		} else {
+			s.Assignments += count
+		}
+	}
+	return stats, nil
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,12 +41,56 @@ func TestRepository_Stats(t *testing.T) {
 	err = repo.AddReviewers(ctx, "p2", []string{"s1"})
 	require.NoError(t, err)
 
-	t.Run("GetReviewerStats", func(t *testing.T) {
-		stats, err := repo.GetReviewerStats(ctx)
+	t.Run("GetAssignmentStats", func(t *testing.T) {
+		stats, err := repo.GetAssignmentStats(ctx, domain.AssignmentStatsFilter{})
 
 		require.NoError(t, err)
 		assert.Len(t, stats, 1)
 		assert.Equal(t, "s1", stats[0].UserID)
 		assert.Equal(t, int64(2), stats[0].AssignmentCount)
 	})
+
+	t.Run("GetAssignmentStats_Filtered", func(t *testing.T) {
+		require.NoError(t, repo.RemoveReviewer(ctx, "p2", "s1"))
+		_, err := repo.UpdatePRStatus(ctx, "p1", domain.PRStatusMerged)
+		require.NoError(t, err)
+		future := time.Now().Add(time.Hour)
+
+		byTeam, err := repo.GetAssignmentStats(ctx, domain.AssignmentStatsFilter{GroupBy: domain.StatsGroupByTeam})
+		require.NoError(t, err)
+		require.Len(t, byTeam, 1)
+		assert.Equal(t, domain.AssignmentStats{TeamName: tName, AssignmentCount: 2, RemovedCount: 1}, byTeam[0])
+
+		byWeek, err := repo.GetAssignmentStats(ctx, domain.AssignmentStatsFilter{GroupBy: domain.StatsGroupByWeek})
+		require.NoError(t, err)
+		require.Len(t, byWeek, 1)
+		require.NotNil(t, byWeek[0].WeekStart)
+		assert.Equal(t, time.Monday, byWeek[0].WeekStart.Weekday())
+
+		merged, err := repo.GetAssignmentStats(ctx, domain.AssignmentStatsFilter{Status: domain.PRStatusMerged})
+		require.NoError(t, err)
+		require.Len(t, merged, 1)
+		assert.Equal(t, int64(1), merged[0].AssignmentCount)
+
+		later, err := repo.GetAssignmentStats(ctx, domain.AssignmentStatsFilter{From: &future})
+		require.NoError(t, err)
+		assert.Empty(t, later)
+	})
+
+	t.Run("PairHistory", func(t *testing.T) {
+		pairs, err := repo.GetPairStats(ctx, tName)
+		require.NoError(t, err)
+		require.Len(t, pairs, 1)
+		assert.Equal(t, "s1", pairs[0].AuthorID)
+		assert.Equal(t, "s1", pairs[0].ReviewerID)
+		assert.Equal(t, int64(2), pairs[0].AssignmentCount)
+
+		recent, err := repo.ListPairAssignments(ctx, "s1", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, recent, 2)
+
+		other, err := repo.GetPairStats(ctx, "no-such-team")
+		require.NoError(t, err)
+		assert.Empty(t, other)
+	})
 }
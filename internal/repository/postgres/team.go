@@ -6,22 +6,31 @@ import (
 	"reviewer/internal/domain"
 )
 
-func (r *repositoryImpl) CreateTeam(ctx context.Context, name string) (domain.Team, error) {
-	q := `INSERT INTO teams (name) VALUES ($1) RETURNING name`
+const teamColumns = `name, default_max_open_reviews, assignment_strategy, rotation_window_days,
+	require_senior, junior_needs_senior, shadow_reviewer, lead_id, reminder_hours, escalation_hours`
+
+func scanTeam(row rowScanner) (domain.Team, error) {
 	var t domain.Team
-	err := r.getQuerier(ctx).QueryRow(ctx, q, name).Scan(&t.Name)
+	err := row.Scan(&t.Name, &t.DefaultMaxOpenReviews, &t.AssignmentStrategy, &t.RotationWindowDays,
+		&t.ReviewRules.RequireSenior, &t.ReviewRules.JuniorNeedsSenior, &t.ReviewRules.ShadowReviewer, &t.LeadID,
+		&t.ReviewSLA.ReminderHours, &t.ReviewSLA.EscalationHours)
+	return t, err
+}
+
+func (r *repositoryImpl) CreateTeam(ctx context.Context, name string) (domain.Team, error) {
+	q := `INSERT INTO teams (name) VALUES ($1) RETURNING ` + teamColumns
+	t, err := scanTeam(r.getQuerier(ctx).QueryRow(ctx, q, name))
 	return t, r.handleError(err)
 }
 
 func (r *repositoryImpl) GetTeamByName(ctx context.Context, name string) (domain.Team, error) {
-	q := `SELECT name FROM teams WHERE name = $1`
-	var t domain.Team
-	err := r.getQuerier(ctx).QueryRow(ctx, q, name).Scan(&t.Name)
+	q := `SELECT ` + teamColumns + ` FROM teams WHERE name = $1`
+	t, err := scanTeam(r.getQuerier(ctx).QueryRow(ctx, q, name))
 	return t, r.handleError(err)
 }
 
 func (r *repositoryImpl) ListTeams(ctx context.Context) ([]domain.Team, error) {
-	q := `SELECT name FROM teams ORDER BY name`
+	q := `SELECT ` + teamColumns + ` FROM teams ORDER BY name`
 	rows, err := r.getQuerier(ctx).Query(ctx, q)
 	if err != nil {
 		return nil, r.handleError(err)
@@ -29,8 +38,8 @@ func (r *repositoryImpl) ListTeams(ctx context.Context) ([]domain.Team, error) {
 	defer rows.Close()
 	var teams []domain.Team
 	for rows.Next() {
-		var t domain.Team
-		if err := rows.Scan(&t.Name); err != nil {
+		t, err := scanTeam(rows)
+		if err != nil {
 			return nil, r.handleError(err)
 		}
 		teams = append(teams, t)
@@ -39,20 +48,40 @@ func (r *repositoryImpl) ListTeams(ctx context.Context) ([]domain.Team, error) {
 }
 
 func (r *repositoryImpl) DeactivateTeamMembers(ctx context.Context, teamName string) ([]domain.User, error) {
-	q := `UPDATE users SET is_active = false WHERE team_name = $1 AND is_active = true RETURNING id, username, team_name, is_active`
-	rows, err := r.getQuerier(ctx).Query(ctx, q, teamName)
-	if err != nil {
-		return nil, r.handleError(err)
-	}
-	defer rows.Close()
+	q := `UPDATE users SET is_active = false WHERE team_name = $1 AND is_active = true RETURNING ` + userColumns
+	return r.queryUsers(ctx, q, teamName)
+}
 
-	deactivatedUsers := make([]domain.User, 0)
-	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
-			return nil, r.handleError(err)
-		}
-		deactivatedUsers = append(deactivatedUsers, u)
-	}
-	return deactivatedUsers, nil
+func (r *repositoryImpl) SetTeamCapacity(ctx context.Context, name string, defaultMaxOpenReviews *int) (domain.Team, error) {
+	q := `UPDATE teams SET default_max_open_reviews = $1 WHERE name = $2 RETURNING ` + teamColumns
+	t, err := scanTeam(r.getQuerier(ctx).QueryRow(ctx, q, defaultMaxOpenReviews, name))
+	return t, r.handleError(err)
+}
+
+func (r *repositoryImpl) SetTeamReviewRules(ctx context.Context, name string, rules domain.TeamReviewRules) (domain.Team, error) {
+	q := `
+		UPDATE teams
+		SET require_senior = $1, junior_needs_senior = $2, shadow_reviewer = $3
+		WHERE name = $4
+		RETURNING ` + teamColumns
+	t, err := scanTeam(r.getQuerier(ctx).QueryRow(ctx, q, rules.RequireSenior, rules.JuniorNeedsSenior, rules.ShadowReviewer, name))
+	return t, r.handleError(err)
+}
+
+func (r *repositoryImpl) SetTeamReviewSLA(ctx context.Context, name string, sla domain.TeamReviewSLA) (domain.Team, error) {
+	q := `UPDATE teams SET reminder_hours = $1, escalation_hours = $2 WHERE name = $3 RETURNING ` + teamColumns
+	t, err := scanTeam(r.getQuerier(ctx).QueryRow(ctx, q, sla.ReminderHours, sla.EscalationHours, name))
+	return t, r.handleError(err)
+}
+
+func (r *repositoryImpl) SetTeamLead(ctx context.Context, name string, leadID *string) (domain.Team, error) {
+	q := `UPDATE teams SET lead_id = $1 WHERE name = $2 RETURNING ` + teamColumns
+	t, err := scanTeam(r.getQuerier(ctx).QueryRow(ctx, q, leadID, name))
+	return t, r.handleError(err)
+}
+
+func (r *repositoryImpl) SetTeamStrategy(ctx context.Context, name string, strategy domain.AssignmentStrategy, windowDays int) (domain.Team, error) {
+	q := `UPDATE teams SET assignment_strategy = $1, rotation_window_days = $2 WHERE name = $3 RETURNING ` + teamColumns
+	t, err := scanTeam(r.getQuerier(ctx).QueryRow(ctx, q, strategy, windowDays, name))
+	return t, r.handleError(err)
 }
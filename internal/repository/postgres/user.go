@@ -2,36 +2,34 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"reviewer/internal/domain"
 )
 
-func (r *repositoryImpl) CreateUser(ctx context.Context, user domain.User) (domain.User, error) {
-	q := `INSERT INTO users (id, username, team_name, is_active) VALUES ($1, $2, $3, $4) RETURNING id, username, team_name, is_active`
-	var u domain.User
-	err := r.getQuerier(ctx).QueryRow(ctx, q, user.ID, user.Username, user.TeamName, user.IsActive).
-		Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
-	return u, r.handleError(err)
+const userColumns = `id, username, team_name, is_active, max_open_reviews, expertise_tags, manager_id, COALESCE(seniority, '')`
+
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-func (r *repositoryImpl) GetUser(ctx context.Context, id string) (domain.User, error) {
-	q := `SELECT id, username, team_name, is_active FROM users WHERE id = $1`
+func scanUser(row rowScanner) (domain.User, error) {
 	var u domain.User
-	err := r.getQuerier(ctx).QueryRow(ctx, q, id).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
-	return u, r.handleError(err)
+	err := row.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.ExpertiseTags, &u.ManagerID, &u.Seniority)
+	return u, err
 }
 
-func (r *repositoryImpl) GetActiveTeamMembers(ctx context.Context, teamName string) ([]domain.User, error) {
-	q := `SELECT id, username, team_name, is_active FROM users WHERE team_name = $1 AND is_active = true`
-	rows, err := r.getQuerier(ctx).Query(ctx, q, teamName)
+func (r *repositoryImpl) queryUsers(ctx context.Context, q string, args ...any) ([]domain.User, error) {
+	rows, err := r.getQuerier(ctx).Query(ctx, q, args...)
 	if err != nil {
 		return nil, r.handleError(err)
 	}
 	defer rows.Close()
-	var users []domain.User
+
+	users := make([]domain.User, 0)
 	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, r.handleError(err)
 		}
 		users = append(users, u)
@@ -39,30 +37,134 @@ func (r *repositoryImpl) GetActiveTeamMembers(ctx context.Context, teamName stri
 	return users, nil
 }
 
+func (r *repositoryImpl) CreateUser(ctx context.Context, user domain.User) (domain.User, error) {
+	q := `INSERT INTO users (id, username, team_name, is_active) VALUES ($1, $2, $3, $4) RETURNING ` + userColumns
+	u, err := scanUser(r.getQuerier(ctx).QueryRow(ctx, q, user.ID, user.Username, user.TeamName, user.IsActive))
+	return u, r.handleError(err)
+}
+
+func (r *repositoryImpl) GetUser(ctx context.Context, id string) (domain.User, error) {
+	q := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	u, err := scanUser(r.getQuerier(ctx).QueryRow(ctx, q, id))
+	return u, r.handleError(err)
+}
+
+// GetActiveTeamMembers возвращает активных участников команды, которые не отсутствуют в момент now
+func (r *repositoryImpl) GetActiveTeamMembers(ctx context.Context, teamName string, now time.Time) ([]domain.User, error) {
+	q := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE team_name = $1
+		  AND is_active = true
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM user_unavailability ua
+			WHERE ua.user_id = users.id AND ua.starts_at <= $2 AND ua.ends_at > $2
+		  )
+		ORDER BY id
+	`
+	return r.queryUsers(ctx, q, teamName, now)
+}
+
+func (r *repositoryImpl) SetUserManager(ctx context.Context, id string, managerID *string) (domain.User, error) {
+	q := `UPDATE users SET manager_id = $1 WHERE id = $2 RETURNING ` + userColumns
+	u, err := scanUser(r.getQuerier(ctx).QueryRow(ctx, q, managerID, id))
+	return u, r.handleError(err)
+}
+
+// GetAvailableUsers возвращает активных пользователей из ids, которые не отсутствуют в момент now
+func (r *repositoryImpl) GetAvailableUsers(ctx context.Context, ids []string, now time.Time) ([]domain.User, error) {
+	q := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id = ANY($1)
+		  AND is_active = true
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM user_unavailability ua
+			WHERE ua.user_id = users.id AND ua.starts_at <= $2 AND ua.ends_at > $2
+		  )
+		ORDER BY id
+	`
+	return r.queryUsers(ctx, q, ids, now)
+}
+
 func (r *repositoryImpl) GetUsersByTeam(ctx context.Context, teamName string) ([]domain.User, error) {
-	q := `SELECT id, username, team_name, is_active FROM users WHERE team_name = $1 ORDER BY id`
-	rows, err := r.getQuerier(ctx).Query(ctx, q, teamName)
+	q := `SELECT ` + userColumns + ` FROM users WHERE team_name = $1 ORDER BY id`
+	return r.queryUsers(ctx, q, teamName)
+}
+
+func (r *repositoryImpl) UpdateUser(ctx context.Context, id string, isActive *bool) (domain.User, error) {
+	if isActive == nil {
+		return r.GetUser(ctx, id)
+	}
+	q := `UPDATE users SET is_active = $1 WHERE id = $2 RETURNING ` + userColumns
+	u, err := scanUser(r.getQuerier(ctx).QueryRow(ctx, q, *isActive, id))
+	return u, r.handleError(err)
+}
+
+func (r *repositoryImpl) SetUserCapacity(ctx context.Context, id string, maxOpenReviews *int) (domain.User, error) {
+	q := `UPDATE users SET max_open_reviews = $1 WHERE id = $2 RETURNING ` + userColumns
+	u, err := scanUser(r.getQuerier(ctx).QueryRow(ctx, q, maxOpenReviews, id))
+	return u, r.handleError(err)
+}
+
+func (r *repositoryImpl) SetUserExpertise(ctx context.Context, id string, tags []string) (domain.User, error) {
+	q := `UPDATE users SET expertise_tags = $1 WHERE id = $2 RETURNING ` + userColumns
+	u, err := scanUser(r.getQuerier(ctx).QueryRow(ctx, q, tags, id))
+	return u, r.handleError(err)
+}
+
+func (r *repositoryImpl) SetUserSeniority(ctx context.Context, id string, seniority domain.Seniority) (domain.User, error) {
+	q := `UPDATE users SET seniority = NULLIF($1, '') WHERE id = $2 RETURNING ` + userColumns
+	u, err := scanUser(r.getQuerier(ctx).QueryRow(ctx, q, seniority, id))
+	return u, r.handleError(err)
+}
+
+// LockReviewerLoads блокирует строки пользователей и возвращает их текущую нагрузку,
+// чтобы параллельные назначения не превысили лимит открытых ревью. Нагрузка считается
+// отдельным запросом уже после блокировки: в READ COMMITTED запрос, дождавшийся блокировки,
+// видит снимок, взятый до ожидания, и не учёл бы ревьюеров, назначенных держателем блокировки
+func (r *repositoryImpl) LockReviewerLoads(ctx context.Context, userIDs []string) ([]domain.ReviewerLoad, error) {
+	q := `SELECT id FROM users WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	if _, err := r.getQuerier(ctx).Exec(ctx, q, userIDs); err != nil {
+		return nil, r.handleError(err)
+	}
+	return r.GetReviewerLoads(ctx, userIDs)
+}
+
+// GetReviewerLoads возвращает текущую нагрузку пользователей без блокировки
+func (r *repositoryImpl) GetReviewerLoads(ctx context.Context, userIDs []string) ([]domain.ReviewerLoad, error) {
+	q := `
+		SELECT u.id,
+		       (SELECT COUNT(*)
+		        FROM pr_reviewers prr
+		        JOIN pull_requests pr ON pr.id = prr.pr_id
+		        WHERE prr.user_id = u.id AND pr.status = 'OPEN'),
+		       COALESCE(u.max_open_reviews, t.default_max_open_reviews)
+		FROM users u
+		JOIN teams t ON t.name = u.team_name
+		WHERE u.id = ANY($1)
+		ORDER BY u.id
+	`
+	rows, err := r.getQuerier(ctx).Query(ctx, q, userIDs)
 	if err != nil {
 		return nil, r.handleError(err)
 	}
 	defer rows.Close()
-	var users []domain.User
+
+	loads := make([]domain.ReviewerLoad, 0, len(userIDs))
 	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		var l domain.ReviewerLoad
+		if err := rows.Scan(&l.UserID, &l.OpenReviews, &l.MaxOpenReviews); err != nil {
 			return nil, r.handleError(err)
 		}
-		users = append(users, u)
+		loads = append(loads, l)
 	}
-	return users, nil
+	return loads, nil
 }
 
-func (r *repositoryImpl) UpdateUser(ctx context.Context, id string, isActive *bool) (domain.User, error) {
-	if isActive == nil {
-		return r.GetUser(ctx, id)
-	}
-	q := `UPDATE users SET is_active = $1 WHERE id = $2 RETURNING id, username, team_name, is_active`
-	var u domain.User
-	err := r.getQuerier(ctx).QueryRow(ctx, q, *isActive, id).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
-	return u, r.handleError(err)
+func (r *repositoryImpl) ActivateUsers(ctx context.Context, ids []string) ([]domain.User, error) {
+	q := `UPDATE users SET is_active = true WHERE id = ANY($1) AND is_active = false RETURNING ` + userColumns
+	return r.queryUsers(ctx, q, ids)
 }
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -76,10 +77,39 @@ func TestRepository_User(t *testing.T) {
 		_, err = repo.CreateUser(ctx, domain.User{ID: "a2", Username: "B", TeamName: tName, IsActive: false})
 		require.NoError(t, err)
 
-		users, err := repo.GetActiveTeamMembers(ctx, tName)
+		users, err := repo.GetActiveTeamMembers(ctx, tName, time.Now())
 
 		require.NoError(t, err)
 		assert.Len(t, users, 1)
 		assert.Equal(t, "a1", users[0].ID)
 	})
+
+	t.Run("LockReviewerLoads_Capacity", func(t *testing.T) {
+		tName := "capacity-repo"
+		_, err := repo.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "cap_a", Username: "A", TeamName: tName, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "cap_r", Username: "R", TeamName: tName, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "pr-cap-repo", Title: "T", AuthorID: "cap_a", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		require.NoError(t, repo.AddReviewers(ctx, "pr-cap-repo", []string{"cap_r"}))
+		teamMax, userMax := 3, 1
+		_, err = repo.SetTeamCapacity(ctx, tName, &teamMax)
+		require.NoError(t, err)
+		updated, err := repo.SetUserCapacity(ctx, "cap_r", &userMax)
+		require.NoError(t, err)
+
+		loads, err := repo.LockReviewerLoads(ctx, []string{"cap_a", "cap_r"})
+
+		require.NoError(t, err)
+		require.Len(t, loads, 2)
+		assert.Equal(t, 1, *updated.MaxOpenReviews)
+		assert.Equal(t, 0, loads[0].OpenReviews)
+		assert.Equal(t, 3, *loads[0].MaxOpenReviews)
+		assert.True(t, loads[0].HasCapacity())
+		assert.Equal(t, 1, loads[1].OpenReviews)
+		assert.False(t, loads[1].HasCapacity())
+	})
 }
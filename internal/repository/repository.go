@@ -12,24 +12,100 @@ type Repository interface {
 	GetTeamByName(ctx context.Context, name string) (domain.Team, error)
 	ListTeams(ctx context.Context) ([]domain.Team, error)
 	DeactivateTeamMembers(ctx context.Context, teamName string) ([]domain.User, error)
+	SetTeamCapacity(ctx context.Context, name string, defaultMaxOpenReviews *int) (domain.Team, error)
+	SetTeamReviewRules(ctx context.Context, name string, rules domain.TeamReviewRules) (domain.Team, error)
+	SetTeamLead(ctx context.Context, name string, leadID *string) (domain.Team, error)
+	SetTeamReviewSLA(ctx context.Context, name string, sla domain.TeamReviewSLA) (domain.Team, error)
+	SetTeamStrategy(ctx context.Context, name string, strategy domain.AssignmentStrategy, windowDays int) (domain.Team, error)
 
 	CreateUser(ctx context.Context, user domain.User) (domain.User, error)
 	GetUser(ctx context.Context, id string) (domain.User, error)
-	GetActiveTeamMembers(ctx context.Context, teamName string) ([]domain.User, error)
+	GetActiveTeamMembers(ctx context.Context, teamName string, now time.Time) ([]domain.User, error)
 	GetUsersByTeam(ctx context.Context, teamName string) ([]domain.User, error)
 	UpdateUser(ctx context.Context, id string, isActive *bool) (domain.User, error)
+	ActivateUsers(ctx context.Context, ids []string) ([]domain.User, error)
+	SetUserCapacity(ctx context.Context, id string, maxOpenReviews *int) (domain.User, error)
+	SetUserExpertise(ctx context.Context, id string, tags []string) (domain.User, error)
+	SetUserSeniority(ctx context.Context, id string, seniority domain.Seniority) (domain.User, error)
+	SetUserManager(ctx context.Context, id string, managerID *string) (domain.User, error)
+	GetAvailableUsers(ctx context.Context, ids []string, now time.Time) ([]domain.User, error)
+	LockReviewerLoads(ctx context.Context, userIDs []string) ([]domain.ReviewerLoad, error)
+	GetReviewerLoads(ctx context.Context, userIDs []string) ([]domain.ReviewerLoad, error)
+
+	CreateReviewExclusion(ctx context.Context, e domain.ReviewExclusion) (domain.ReviewExclusion, error)
+	DeleteReviewExclusion(ctx context.Context, id int64) error
+	ListReviewExclusions(ctx context.Context, userID string) ([]domain.ReviewExclusion, error)
+	ListConflictingReviewers(ctx context.Context, authorID string) ([]string, error)
+
+	CreateRepository(ctx context.Context, name string) (domain.Repository, error)
+	GetRepository(ctx context.Context, name string) (domain.Repository, error)
+	SetRepositoryOwners(ctx context.Context, name string, teamNames []string) error
+
+	SaveCodeOwners(ctx context.Context, teamName, content string) (domain.CodeOwnersFile, error)
+	ListCodeOwners(ctx context.Context) ([]domain.CodeOwnersFile, error)
+
+	SaveRuleSet(ctx context.Context, teamName, content string) (domain.RuleSetVersion, error)
+	GetRuleSet(ctx context.Context, teamName string, version int) (domain.RuleSetVersion, error)
+	ListRuleSetVersions(ctx context.Context, teamName string) ([]int, error)
+	SaveRuleTrace(ctx context.Context, prID string, trace domain.RuleTrace) error
+	GetRuleTrace(ctx context.Context, prID string) (domain.RuleTrace, error)
+
+	CreateUnavailability(ctx context.Context, u domain.Unavailability) (domain.Unavailability, error)
+	ListUnavailability(ctx context.Context, userID string) ([]domain.Unavailability, error)
+	ListAbsencesToRelease(ctx context.Context, now time.Time, minDuration time.Duration) ([]domain.Unavailability, error)
+	MarkUnavailabilityReleased(ctx context.Context, id int64, at time.Time) (time.Time, error)
 
 	CreatePR(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error)
 	GetPR(ctx context.Context, id string) (domain.PullRequest, error)
 	GetPRForUpdate(ctx context.Context, id string) (domain.PullRequest, error)
+	AddPRExclusions(ctx context.Context, prID string, userIDs []string) error
 	UpdatePRStatus(ctx context.Context, id string, status domain.PRStatus) (time.Time, error)
+	ListUnderstaffedPRs(ctx context.Context, teamName string) ([]domain.UnderstaffedPR, error)
 
 	AddReviewers(ctx context.Context, prID string, reviewerIDs []string) error
+	AddMatchedReviewers(ctx context.Context, prID string, matches []domain.ReviewerMatch) error
+	GetReviewerMatches(ctx context.Context, prID string) ([]domain.ReviewerMatch, error)
 	RemoveReviewer(ctx context.Context, prID, userID string) error
+	AddManualReviewer(ctx context.Context, prID, userID, actorID string) error
+	RecordReviewerRemoval(ctx context.Context, prID, userID, actorID string) error
+	RecordDecline(ctx context.Context, prID, userID string, reason domain.DeclineReason) error
+	AddShadowReviewer(ctx context.Context, prID, userID string) error
 	ListPRsByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequestShort, error)
 	RemoveReviewersFromOpenPRs(ctx context.Context, userIDs []string) ([]domain.PullRequestShort, error)
+	ListOpenReviewAssignments(ctx context.Context, userIDs []string) ([]domain.ReviewAssignment, error)
+
+	AddReview(ctx context.Context, review domain.Review) (domain.Review, error)
+	ListStaleReviews(ctx context.Context, now time.Time) ([]domain.StaleReview, error)
+	MarkReminded(ctx context.Context, prID, userID string, at time.Time) error
+	MarkEscalationFailed(ctx context.Context, prID, userID string, at time.Time) error
+	TryAdvisoryLock(ctx context.Context, key int64) (bool, error)
+
+	GetNotificationPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, error)
+	SetNotificationPreferences(ctx context.Context, prefs domain.NotificationPreferences) (domain.NotificationPreferences, error)
+	EnqueueNotifications(ctx context.Context, notifications []domain.Notification) error
+	ListPendingNotifications(ctx context.Context, maxAttempts int) ([]domain.PendingNotification, error)
+	MarkNotificationsSent(ctx context.Context, ids []int64, at time.Time) error
+	RecordNotificationFailure(ctx context.Context, id int64, reason string) error
+
+	ListAwaitingPRs(ctx context.Context, authorID string) ([]domain.AwaitingPR, error)
+	ListMergedPRs(ctx context.Context, userID string, since time.Time) ([]domain.MergedPR, error)
+	ListDigestNotifications(ctx context.Context, userID string) ([]domain.Notification, error)
+	ListDigestRecipients(ctx context.Context) ([]domain.DigestRecipient, error)
+	MarkDigestSent(ctx context.Context, userID string, at time.Time) error
+
+	CreateOperation(ctx context.Context, op *domain.Operation) (*domain.Operation, error)
+	GetOperationForUpdate(ctx context.Context, id int64) (domain.Operation, error)
+	GetLastPendingOperationForUpdate(ctx context.Context, teamName string, kind domain.OperationKind) (domain.Operation, error)
+	RestoreRemovedReviews(ctx context.Context, op domain.Operation) ([]domain.ReviewAssignment, error)
+	MarkOperationUndone(ctx context.Context, id int64) (time.Time, error)
 
-	GetReviewerStats(ctx context.Context) ([]domain.UserAssignmentStats, error)
+	GetAssignmentStats(ctx context.Context, filter domain.AssignmentStatsFilter) ([]domain.AssignmentStats, error)
+	ListPairAssignments(ctx context.Context, authorID string, since time.Time) ([]domain.PairAssignment, error)
+	GetPairStats(ctx context.Context, teamName string) ([]domain.PairStats, error)
+	ListAssignmentHistory(ctx context.Context, prID string) ([]domain.AssignmentRecord, error)
+	GetDeclineStats(ctx context.Context, teamName string) ([]domain.DeclineStats, error)
+	GetTeamLatency(ctx context.Context, filter domain.LatencyFilter) ([]domain.LatencyStats, error)
+	GetReviewerLatency(ctx context.Context, filter domain.LatencyFilter) ([]domain.LatencyStats, error)
 }
 
 type Transactor interface {
@@ -0,0 +1,381 @@
+package rules
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidRules = errors.New("invalid rule set")
+
+var validSeniority = []string{"JUNIOR", "MID", "SENIOR"}
+
+// PRCondition ограничивает правило PR с заданными атрибутами; пустое условие выполняется всегда
+type PRCondition struct {
+	LabelsAny       []string `yaml:"labels_any"`
+	AuthorSeniority []string `yaml:"author_seniority"`
+	Authors         []string `yaml:"authors"`
+}
+
+// Matcher описывает кандидатов, к которым применяется правило; пустой матчер подходит всем
+type Matcher struct {
+	Users        []string `yaml:"users"`
+	Seniority    []string `yaml:"seniority"`
+	ExpertiseAny []string `yaml:"expertise_any"`
+}
+
+// Filter исключает подходящих кандидатов
+type Filter struct {
+	Name    string      `yaml:"name"`
+	When    PRCondition `yaml:"when"`
+	Exclude Matcher     `yaml:"exclude"`
+}
+
+// Requirement требует не менее Count ревьюеров, подходящих под Require
+type Requirement struct {
+	Name    string      `yaml:"name"`
+	When    PRCondition `yaml:"when"`
+	Require Matcher     `yaml:"require"`
+	Count   int         `yaml:"count"`
+}
+
+// Weight добавляет подходящим кандидатам Weight очков и LoadFactor очков за каждое открытое ревью
+type Weight struct {
+	Name       string      `yaml:"name"`
+	When       PRCondition `yaml:"when"`
+	Match      Matcher     `yaml:"match"`
+	Weight     float64     `yaml:"weight"`
+	LoadFactor float64     `yaml:"load_factor"`
+}
+
+type RuleSet struct {
+	Filters      []Filter      `yaml:"filters"`
+	Requirements []Requirement `yaml:"requirements"`
+	Weights      []Weight      `yaml:"weights"`
+}
+
+// Parse разбирает и проверяет набор правил в YAML или JSON (JSON — подмножество YAML).
+// Неизвестные поля считаются ошибкой
+func Parse(r io.Reader) (*RuleSet, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading rule set: %w", err)
+	}
+	rs := &RuleSet{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return rs, nil
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(rs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+	if err := rs.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+	return rs, nil
+}
+
+// Empty сообщает, что в наборе нет ни одного правила
+func (rs *RuleSet) Empty() bool {
+	return len(rs.Filters) == 0 && len(rs.Requirements) == 0 && len(rs.Weights) == 0
+}
+
+func (rs *RuleSet) validate() error {
+	names := make(map[string]bool)
+	checkName := func(kind, name string) error {
+		if name == "" {
+			return fmt.Errorf("%s without name", kind)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate rule name %q", name)
+		}
+		names[name] = true
+		return nil
+	}
+
+	for i := range rs.Filters {
+		f := &rs.Filters[i]
+		if err := checkName("filter", f.Name); err != nil {
+			return err
+		}
+		if f.Exclude.empty() {
+			return fmt.Errorf("filter %q: exclude must not be empty", f.Name)
+		}
+		if err := f.When.normalize(); err != nil {
+			return fmt.Errorf("filter %q: %v", f.Name, err)
+		}
+		if err := f.Exclude.normalize(); err != nil {
+			return fmt.Errorf("filter %q: %v", f.Name, err)
+		}
+	}
+	for i := range rs.Requirements {
+		req := &rs.Requirements[i]
+		if err := checkName("requirement", req.Name); err != nil {
+			return err
+		}
+		if req.Count < 0 {
+			return fmt.Errorf("requirement %q: count must not be negative", req.Name)
+		}
+		if req.Count == 0 {
+			req.Count = 1
+		}
+		if err := req.When.normalize(); err != nil {
+			return fmt.Errorf("requirement %q: %v", req.Name, err)
+		}
+		if err := req.Require.normalize(); err != nil {
+			return fmt.Errorf("requirement %q: %v", req.Name, err)
+		}
+	}
+	for i := range rs.Weights {
+		w := &rs.Weights[i]
+		if err := checkName("weight", w.Name); err != nil {
+			return err
+		}
+		if w.Weight == 0 && w.LoadFactor == 0 {
+			return fmt.Errorf("weight %q: weight or load_factor is required", w.Name)
+		}
+		if err := w.When.normalize(); err != nil {
+			return fmt.Errorf("weight %q: %v", w.Name, err)
+		}
+		if err := w.Match.normalize(); err != nil {
+			return fmt.Errorf("weight %q: %v", w.Name, err)
+		}
+	}
+	return nil
+}
+
+func normalizeSeniority(values []string) ([]string, error) {
+	for i, v := range values {
+		values[i] = strings.ToUpper(strings.TrimSpace(v))
+		if !slices.Contains(validSeniority, values[i]) {
+			return nil, fmt.Errorf("unknown seniority %q", v)
+		}
+	}
+	return values, nil
+}
+
+func lowerAll(values []string) []string {
+	for i, v := range values {
+		values[i] = strings.ToLower(strings.TrimSpace(v))
+	}
+	return values
+}
+
+func (c *PRCondition) normalize() (err error) {
+	c.LabelsAny = lowerAll(c.LabelsAny)
+	c.AuthorSeniority, err = normalizeSeniority(c.AuthorSeniority)
+	return err
+}
+
+func (m *Matcher) normalize() (err error) {
+	m.ExpertiseAny = lowerAll(m.ExpertiseAny)
+	m.Seniority, err = normalizeSeniority(m.Seniority)
+	return err
+}
+
+func (m Matcher) empty() bool {
+	return len(m.Users) == 0 && len(m.Seniority) == 0 && len(m.ExpertiseAny) == 0
+}
+
+// PullRequest — атрибуты PR, доступные условиям правил
+type PullRequest struct {
+	AuthorID        string
+	AuthorSeniority string
+	Labels          []string
+}
+
+type Candidate struct {
+	ID          string
+	Seniority   string
+	Expertise   []string
+	OpenReviews int
+}
+
+func containsAny(values, wanted []string) bool {
+	for _, w := range wanted {
+		if slices.Contains(values, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c PRCondition) matches(pr PullRequest) bool {
+	if len(c.LabelsAny) > 0 && !containsAny(pr.Labels, c.LabelsAny) {
+		return false
+	}
+	if len(c.AuthorSeniority) > 0 && !slices.Contains(c.AuthorSeniority, pr.AuthorSeniority) {
+		return false
+	}
+	if len(c.Authors) > 0 && !slices.Contains(c.Authors, pr.AuthorID) {
+		return false
+	}
+	return true
+}
+
+func (m Matcher) matches(c Candidate) bool {
+	if len(m.Users) > 0 && !slices.Contains(m.Users, c.ID) {
+		return false
+	}
+	if len(m.Seniority) > 0 && !slices.Contains(m.Seniority, c.Seniority) {
+		return false
+	}
+	if len(m.ExpertiseAny) > 0 && !containsAny(c.Expertise, m.ExpertiseAny) {
+		return false
+	}
+	return true
+}
+
+type StepKind string
+
+const (
+	StepFilter      StepKind = "FILTER"
+	StepWeight      StepKind = "WEIGHT"
+	StepRequirement StepKind = "REQUIREMENT"
+	StepFill        StepKind = "FILL"
+)
+
+// Step — одна запись трассировки: какое правило и как повлияло на кандидата
+type Step struct {
+	Kind   StepKind
+	Rule   string
+	UserID string
+	Detail string
+}
+
+type Result struct {
+	Selected []string
+	// Unmet — имена требований, которые не удалось выполнить
+	Unmet []string
+	Trace []Step
+}
+
+// Evaluate выбирает до n ревьюеров из candidates. assigned — уже назначенные на PR ревьюеры,
+// они учитываются при проверке требований. Порядок: фильтры, веса, требования
+// (в порядке объявления), затем добор кандидатами с наибольшим счётом; равные счёты
+// упорядочиваются с помощью rng, так что при том же зерне результат воспроизводим
+func (rs *RuleSet) Evaluate(pr PullRequest, candidates, assigned []Candidate, n int, rng *rand.Rand) Result {
+	res := Result{Selected: []string{}, Unmet: []string{}, Trace: []Step{}}
+
+	eligible := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		excludedBy := ""
+		for _, f := range rs.Filters {
+			if f.When.matches(pr) && f.Exclude.matches(c) {
+				excludedBy = f.Name
+				break
+			}
+		}
+		if excludedBy != "" {
+			res.Trace = append(res.Trace, Step{Kind: StepFilter, Rule: excludedBy, UserID: c.ID, Detail: "excluded"})
+			continue
+		}
+		eligible = append(eligible, c)
+	}
+
+	scores := make(map[string]float64, len(eligible))
+	for _, w := range rs.Weights {
+		if !w.When.matches(pr) {
+			continue
+		}
+		for _, c := range eligible {
+			if !w.Match.matches(c) {
+				continue
+			}
+			delta := w.Weight + w.LoadFactor*float64(c.OpenReviews)
+			if delta == 0 {
+				continue
+			}
+			scores[c.ID] += delta
+			res.Trace = append(res.Trace, Step{Kind: StepWeight, Rule: w.Name, UserID: c.ID, Detail: fmt.Sprintf("%+g", delta)})
+		}
+	}
+
+	ranked := slices.Clone(eligible)
+	rng.Shuffle(len(ranked), func(i, j int) {
+		ranked[i], ranked[j] = ranked[j], ranked[i]
+	})
+	slices.SortStableFunc(ranked, func(a, b Candidate) int {
+		return cmp.Compare(scores[b.ID], scores[a.ID])
+	})
+
+	chosen := make(map[string]bool)
+	pick := func(c Candidate, kind StepKind, rule string) {
+		chosen[c.ID] = true
+		res.Selected = append(res.Selected, c.ID)
+		res.Trace = append(res.Trace, Step{Kind: kind, Rule: rule, UserID: c.ID, Detail: fmt.Sprintf("selected with score %g", scores[c.ID])})
+	}
+
+	for _, req := range rs.Requirements {
+		if !req.When.matches(pr) {
+			continue
+		}
+		have := 0
+		for _, c := range assigned {
+			if req.Require.matches(c) {
+				have++
+			}
+		}
+		for _, c := range ranked {
+			if chosen[c.ID] && req.Require.matches(c) {
+				have++
+			}
+		}
+		for _, c := range ranked {
+			if have >= req.Count || len(res.Selected) >= n {
+				break
+			}
+			if !chosen[c.ID] && req.Require.matches(c) {
+				pick(c, StepRequirement, req.Name)
+				have++
+			}
+		}
+		if have < req.Count {
+			res.Unmet = append(res.Unmet, req.Name)
+			res.Trace = append(res.Trace, Step{Kind: StepRequirement, Rule: req.Name, Detail: fmt.Sprintf("unmet: %d of %d", have, req.Count)})
+		}
+	}
+
+	for _, c := range ranked {
+		if len(res.Selected) >= n {
+			break
+		}
+		if !chosen[c.ID] {
+			pick(c, StepFill, "")
+		}
+	}
+	return res
+}
+
+// Users возвращает идентификаторы пользователей, упомянутых в правилах
+func (rs *RuleSet) Users() []string {
+	var ids []string
+	add := func(values []string) {
+		for _, v := range values {
+			if !slices.Contains(ids, v) {
+				ids = append(ids, v)
+			}
+		}
+	}
+	for _, f := range rs.Filters {
+		add(f.When.Authors)
+		add(f.Exclude.Users)
+	}
+	for _, r := range rs.Requirements {
+		add(r.When.Authors)
+		add(r.Require.Users)
+	}
+	for _, w := range rs.Weights {
+		add(w.When.Authors)
+		add(w.Match.Users)
+	}
+	return ids
+}
@@ -0,0 +1,154 @@
+package rules
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("YAML", func(t *testing.T) {
+		data := `
+filters:
+  - name: no-juniors-on-hotfix
+    when: { labels_any: [Hotfix] }
+    exclude: { seniority: [junior] }
+requirements:
+  - name: db-expert
+    when: { labels_any: [db] }
+    require: { expertise_any: [DB] }
+weights:
+  - name: spread-load
+    load_factor: -1
+`
+		rs, err := Parse(strings.NewReader(data))
+
+		require.NoError(t, err)
+		require.Len(t, rs.Filters, 1, newRand())
+		assert.Equal(t, []string{"hotfix"}, rs.Filters[0].When.LabelsAny)
+		assert.Equal(t, []string{"JUNIOR"}, rs.Filters[0].Exclude.Seniority)
+		assert.Equal(t, 1, rs.Requirements[0].Count)
+		assert.Equal(t, []string{"db"}, rs.Requirements[0].Require.ExpertiseAny)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data := `{
+	"requirements": [{"name": "senior", "require": {"seniority": ["SENIOR"]}, "count": 1}]
+}`
+		rs, err := Parse(strings.NewReader(data))
+
+		require.NoError(t, err)
+		require.Len(t, rs.Requirements, 1, newRand())
+		assert.False(t, rs.Empty())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		rs, err := Parse(strings.NewReader("  \n"))
+
+		require.NoError(t, err)
+		assert.True(t, rs.Empty())
+	})
+
+	invalid := map[string]string{
+		"UnknownField":     "weights:\n  - name: w\n    weight: 1\n    bonus: 2\n",
+		"MissingName":      "weights:\n  - weight: 1\n",
+		"DuplicateName":    "weights:\n  - name: a\n    weight: 1\nrequirements:\n  - name: a\n",
+		"EmptyExclude":     "filters:\n  - name: f\n",
+		"UnknownSeniority": "requirements:\n  - name: r\n    require: { seniority: [lead] }\n",
+		"ZeroWeight":       "weights:\n  - name: w\n",
+		"NegativeCount":    "requirements:\n  - name: r\n    count: -1\n",
+		"Malformed":        "filters: [",
+	}
+	for name, data := range invalid {
+		t.Run("Invalid_"+name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(data))
+
+			assert.ErrorIs(t, err, ErrInvalidRules)
+		})
+	}
+}
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 0))
+}
+
+func TestEvaluate(t *testing.T) {
+	candidates := []Candidate{
+		{ID: "junior", Seniority: "JUNIOR"},
+		{ID: "mid", Seniority: "MID", OpenReviews: 3},
+		{ID: "senior", Seniority: "SENIOR", OpenReviews: 5},
+		{ID: "dba", Seniority: "MID", Expertise: []string{"db"}},
+	}
+
+	t.Run("RequirementsThenScore", func(t *testing.T) {
+		rs, err := Parse(strings.NewReader(`
+filters:
+  - name: no-juniors
+    exclude: { seniority: [JUNIOR] }
+requirements:
+  - name: senior
+    require: { seniority: [SENIOR] }
+weights:
+  - name: spread-load
+    load_factor: -1
+`))
+		require.NoError(t, err)
+
+		res := rs.Evaluate(PullRequest{AuthorID: "a"}, candidates, nil, 2, newRand())
+
+		assert.Equal(t, []string{"senior", "dba"}, res.Selected)
+		assert.Empty(t, res.Unmet)
+		assert.Contains(t, res.Trace, Step{Kind: StepFilter, Rule: "no-juniors", UserID: "junior", Detail: "excluded"})
+		assert.Contains(t, res.Trace, Step{Kind: StepRequirement, Rule: "senior", UserID: "senior", Detail: "selected with score -5"})
+	})
+
+	t.Run("AssignedSatisfiesRequirement", func(t *testing.T) {
+		rs, err := Parse(strings.NewReader("requirements:\n  - name: dba\n    require: { expertise_any: [db] }\n" +
+			"weights:\n  - name: prefer-mid\n    match: { users: [mid] }\n    weight: 10\n"))
+		require.NoError(t, err)
+
+		res := rs.Evaluate(PullRequest{}, candidates[:3], []Candidate{candidates[3]}, 1, newRand())
+
+		assert.Equal(t, []string{"mid"}, res.Selected)
+		assert.Empty(t, res.Unmet)
+	})
+
+	t.Run("UnmetRequirement", func(t *testing.T) {
+		rs, err := Parse(strings.NewReader(`
+requirements:
+  - name: two-seniors-for-juniors
+    when: { author_seniority: [JUNIOR] }
+    require: { seniority: [SENIOR] }
+    count: 2
+`))
+		require.NoError(t, err)
+
+		res := rs.Evaluate(PullRequest{AuthorSeniority: "JUNIOR"}, candidates, nil, 2, newRand())
+
+		assert.Equal(t, []string{"two-seniors-for-juniors"}, res.Unmet)
+		assert.Len(t, res.Selected, 2, newRand())
+		assert.Equal(t, "senior", res.Selected[0])
+	})
+
+	t.Run("ConditionNotMet", func(t *testing.T) {
+		rs, err := Parse(strings.NewReader("filters:\n  - name: f\n    when: { labels_any: [hotfix] }\n    exclude: { users: [mid] }\n"))
+		require.NoError(t, err)
+
+		res := rs.Evaluate(PullRequest{Labels: []string{"db"}}, candidates[1:2], nil, 1, newRand())
+
+		assert.Equal(t, []string{"mid"}, res.Selected)
+	})
+
+	t.Run("SameSeedSameResult", func(t *testing.T) {
+		rs, err := Parse(strings.NewReader("weights:\n  - name: flat\n    weight: 1\n"))
+		require.NoError(t, err)
+
+		first := rs.Evaluate(PullRequest{}, candidates, nil, 2, rand.New(rand.NewPCG(42, 0)))
+		second := rs.Evaluate(PullRequest{}, candidates, nil, 2, rand.New(rand.NewPCG(42, 0)))
+
+		assert.Equal(t, first.Selected, second.Selected)
+	})
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/ical"
+)
+
+func (s *Service) AddUnavailability(ctx context.Context, period domain.Unavailability) (domain.Unavailability, error) {
+	created, err := s.AddUnavailabilities(ctx, period.UserID, []domain.Unavailability{period})
+	if err != nil {
+		return domain.Unavailability{}, err
+	}
+	return created[0], nil
+}
+
+func (s *Service) AddUnavailabilities(ctx context.Context, userID string, periods []domain.Unavailability) ([]domain.Unavailability, error) {
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	created := make([]domain.Unavailability, 0, len(periods))
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		for _, p := range periods {
+			p.UserID = userID
+			u, err := s.repo.CreateUnavailability(ctxTx, p)
+			if err != nil {
+				return err
+			}
+			if s.shouldRelease(u) {
+				if u.ReleasedAt, err = s.releaseAbsence(ctxTx, u); err != nil {
+					return err
+				}
+			}
+			created = append(created, u)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ImportUnavailability добавляет отсутствия из событий календаря. События нулевой длины
+// и уже закончившиеся пропускаются; skipped — их количество
+func (s *Service) ImportUnavailability(ctx context.Context, userID string, events []ical.Event) (created []domain.Unavailability, skipped int, err error) {
+	now := s.now()
+	periods := make([]domain.Unavailability, 0, len(events))
+	for _, e := range events {
+		if !e.End.After(e.Start) || !e.End.After(now) {
+			continue
+		}
+		periods = append(periods, domain.Unavailability{From: e.Start, To: e.End, Reason: e.Summary})
+	}
+	created, err = s.AddUnavailabilities(ctx, userID, periods)
+	if err != nil {
+		return nil, 0, err
+	}
+	return created, len(events) - len(periods), nil
+}
+
+func (s *Service) ListUnavailability(ctx context.Context, userID string) ([]domain.Unavailability, error) {
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListUnavailability(ctx, userID)
+}
+
+// ReleaseStartedAbsences снимает ревью с пользователей, чьё длительное отсутствие уже началось
+func (s *Service) ReleaseStartedAbsences(ctx context.Context) ([]domain.Unavailability, error) {
+	if s.absenceReleaseThreshold <= 0 {
+		return []domain.Unavailability{}, nil
+	}
+
+	var released []domain.Unavailability
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		absences, err := s.repo.ListAbsencesToRelease(ctxTx, s.now(), s.absenceReleaseThreshold)
+		if err != nil {
+			return fmt.Errorf("listing absences: %w", err)
+		}
+		released = make([]domain.Unavailability, 0, len(absences))
+		for _, a := range absences {
+			if a.ReleasedAt, err = s.releaseAbsence(ctxTx, a); err != nil {
+				return err
+			}
+			released = append(released, a)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+func (s *Service) shouldRelease(u domain.Unavailability) bool {
+	now := s.now()
+	return s.absenceReleaseThreshold > 0 &&
+		!u.From.After(now) && u.To.After(now) &&
+		u.To.Sub(u.From) >= s.absenceReleaseThreshold
+}
+
+func (s *Service) releaseAbsence(ctx context.Context, u domain.Unavailability) (*time.Time, error) {
+	if _, _, err := s.releaseUserReviews(ctx, u.UserID); err != nil {
+		return nil, fmt.Errorf("releasing reviews of %s: %w", u.UserID, err)
+	}
+	releasedAt, err := s.repo.MarkUnavailabilityReleased(ctx, u.ID, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("marking absence released: %w", err)
+	}
+	return &releasedAt, nil
+}
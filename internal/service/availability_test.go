@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_Availability(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo, WithAbsenceReleaseThreshold(48*time.Hour))
+	now := time.Now()
+
+	t.Run("AddUnavailability_ExcludedFromNewAssignments", func(t *testing.T) {
+		tName := "avail-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "as_a", "A", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "as_r1", "R1", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "as_r2", "R2", tName, true)
+		require.NoError(t, err)
+		_, err = svc.AddUnavailability(ctx, domain.Unavailability{
+			UserID: "as_r1", From: now.Add(-time.Hour), To: now.Add(2 * time.Hour), Reason: "doctor",
+		})
+		require.NoError(t, err)
+
+		pr, err := svc.CreatePR(ctx, "pr-avail", "T", "as_a", CreatePROptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"as_r2"}, pr.Reviewers)
+	})
+
+	t.Run("AddUnavailability_UsesServiceClock", func(t *testing.T) {
+		tName := "avail-clock"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"ac_a", "ac_r1", "ac_r2"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+		_, err = svc.AddUnavailability(ctx, domain.Unavailability{
+			UserID: "ac_r1", From: now.Add(24 * time.Hour), To: now.Add(26 * time.Hour), Reason: "trip",
+		})
+		require.NoError(t, err)
+		later := New(repo, WithClock(func() time.Time { return now.Add(25 * time.Hour) }))
+
+		pr, err := later.CreatePR(ctx, "pr-avail-clock", "T", "ac_a", CreatePROptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ac_r2"}, pr.Reviewers)
+	})
+
+	t.Run("AddUnavailability_LongAbsenceReleasesReviews", func(t *testing.T) {
+		tName := "avail-release"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ar_a", "A", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ar_old", "Old", tName, true)
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "pr-avail-rel", Title: "T", AuthorID: "ar_a", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		err = repo.AddReviewers(ctx, "pr-avail-rel", []string{"ar_old"})
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "ar_new", Username: "New", TeamName: tName, IsActive: true})
+		require.NoError(t, err)
+
+		period, err := svc.AddUnavailability(ctx, domain.Unavailability{
+			UserID: "ar_old", From: now.Add(-time.Minute), To: now.Add(14 * 24 * time.Hour), Reason: "parental leave",
+		})
+
+		require.NoError(t, err)
+		assert.NotNil(t, period.ReleasedAt)
+		pr, _ := repo.GetPR(ctx, "pr-avail-rel")
+		assert.Equal(t, []string{"ar_new"}, pr.Reviewers)
+	})
+
+	t.Run("ReleaseStartedAbsences_SkipsShortAbsences", func(t *testing.T) {
+		tName := "avail-short"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ash_a", "A", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ash_r", "R", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreatePR(ctx, "pr-avail-short", "T", "ash_a", CreatePROptions{})
+		require.NoError(t, err)
+		_, err = svc.AddUnavailability(ctx, domain.Unavailability{
+			UserID: "ash_r", From: now.Add(-time.Minute), To: now.Add(time.Hour), Reason: "lunch",
+		})
+		require.NoError(t, err)
+
+		released, err := svc.ReleaseStartedAbsences(ctx)
+
+		require.NoError(t, err)
+		for _, a := range released {
+			assert.NotEqual(t, "ash_r", a.UserID)
+		}
+		pr, _ := repo.GetPR(ctx, "pr-avail-short")
+		assert.Equal(t, []string{"ash_r"}, pr.Reviewers)
+	})
+
+	t.Run("ListUnavailability_UnknownUser", func(t *testing.T) {
+		_, err := svc.ListUnavailability(ctx, "unknown")
+
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
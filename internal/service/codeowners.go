@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"reviewer/internal/codeowners"
+	"reviewer/internal/domain"
+)
+
+// SetCodeOwners сохраняет файл владения команды после проверки синтаксиса и владельцев
+func (s *Service) SetCodeOwners(ctx context.Context, teamName, content string) (domain.CodeOwnersFile, int, error) {
+	if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+		return domain.CodeOwnersFile{}, 0, err
+	}
+	rs, err := codeowners.Parse(strings.NewReader(content))
+	if err != nil {
+		return domain.CodeOwnersFile{}, 0, err
+	}
+	for _, rule := range rs.Rules() {
+		for _, o := range rule.Owners {
+			if o.Team != "" {
+				_, err = s.repo.GetTeamByName(ctx, o.Team)
+			} else {
+				_, err = s.repo.GetUser(ctx, o.User)
+			}
+			if err != nil {
+				if errors.Is(err, domain.ErrNotFound) {
+					return domain.CodeOwnersFile{}, 0, fmt.Errorf("%w: line %d: %s", domain.ErrUnknownOwner, rule.Line, o)
+				}
+				return domain.CodeOwnersFile{}, 0, err
+			}
+		}
+	}
+
+	f, err := s.repo.SaveCodeOwners(ctx, teamName, content)
+	if err != nil {
+		return domain.CodeOwnersFile{}, 0, err
+	}
+	return f, len(rs.Rules()), nil
+}
+
+func normalizePaths(paths []string) []string {
+	result := make([]string, 0, len(paths))
+	for _, p := range paths {
+		p = strings.TrimPrefix(strings.TrimSpace(p), "/")
+		if p != "" && !slices.Contains(result, p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+type pathOwner struct {
+	user domain.User
+	rule domain.OwnershipRule
+}
+
+// resolvePathOwners сопоставляет каждому пути доступных владельцев по файлам всех команд
+func (s *Service) resolvePathOwners(ctx context.Context, paths []string, excluded map[string]bool) (map[string][]pathOwner, error) {
+	files, err := s.repo.ListCodeOwners(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing codeowners: %w", err)
+	}
+
+	type ruleOwner struct {
+		owner codeowners.Owner
+		rule  domain.OwnershipRule
+	}
+	matched := make(map[string][]ruleOwner)
+	var userIDs []string
+	for _, f := range files {
+		rs, err := codeowners.Parse(strings.NewReader(f.Content))
+		if err != nil {
+			return nil, fmt.Errorf("parsing codeowners of team %s: %w", f.TeamName, err)
+		}
+		for _, p := range paths {
+			rule, ok := rs.Match(p)
+			if !ok {
+				continue
+			}
+			ref := domain.OwnershipRule{TeamName: f.TeamName, Line: rule.Line, Pattern: rule.Pattern, Path: p}
+			for _, o := range rule.Owners {
+				matched[p] = append(matched[p], ruleOwner{owner: o, rule: ref})
+				if o.User != "" {
+					userIDs = append(userIDs, o.User)
+				}
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+
+	users := make(map[string]domain.User)
+	if len(userIDs) > 0 {
+		available, err := s.repo.GetAvailableUsers(ctx, userIDs, s.now())
+		if err != nil {
+			return nil, fmt.Errorf("getting owners: %w", err)
+		}
+		for _, u := range available {
+			users[u.ID] = u
+		}
+	}
+	teamMembers := make(map[string][]domain.User)
+
+	result := make(map[string][]pathOwner, len(matched))
+	for _, p := range paths {
+		seen := make(map[string]bool)
+		for _, ro := range matched[p] {
+			var candidates []domain.User
+			if ro.owner.Team != "" {
+				members, ok := teamMembers[ro.owner.Team]
+				if !ok {
+					members, err = s.repo.GetActiveTeamMembers(ctx, ro.owner.Team, s.now())
+					if err != nil {
+						return nil, fmt.Errorf("getting owner team members: %w", err)
+					}
+					teamMembers[ro.owner.Team] = members
+				}
+				candidates = members
+			} else if u, ok := users[ro.owner.User]; ok {
+				candidates = []domain.User{u}
+			}
+			for _, u := range candidates {
+				if excluded[u.ID] || seen[u.ID] {
+					continue
+				}
+				seen[u.ID] = true
+				result[p] = append(result[p], pathOwner{user: u, rule: ro.rule})
+			}
+		}
+	}
+	return result, nil
+}
+
+// ownerOutcome — один из возможных исходов pickCodeOwners и его вероятность
+type ownerOutcome struct {
+	p        float64
+	selected []domain.User
+}
+
+// ownerOutcomes перечисляет все исходы pickCodeOwners с их вероятностями,
+// повторяя его обход путей и равновероятный выбор среди владельцев пути
+func ownerOutcomes(paths []string, owners map[string][]pathOwner, loads reviewerLoads, n int) []ownerOutcome {
+	var outcomes []ownerOutcome
+	var walk func(i int, selected []domain.User, covered map[string]bool, p float64)
+	walk = func(i int, selected []domain.User, covered map[string]bool, p float64) {
+		for ; i < len(paths) && len(selected) < n; i++ {
+			if covered[paths[i]] {
+				continue
+			}
+			var options []domain.User
+			for _, o := range owners[paths[i]] {
+				taken := slices.ContainsFunc(selected, func(u domain.User) bool { return u.ID == o.user.ID })
+				if !taken && loads[o.user.ID].HasCapacity() {
+					options = append(options, o.user)
+				}
+			}
+			if len(options) == 0 {
+				continue
+			}
+			for _, u := range options {
+				next := maps.Clone(covered)
+				for _, q := range paths {
+					if slices.ContainsFunc(owners[q], func(o pathOwner) bool { return o.user.ID == u.ID }) {
+						next[q] = true
+					}
+				}
+				walk(i+1, append(slices.Clone(selected), u), next, p/float64(len(options)))
+			}
+			return
+		}
+		outcomes = append(outcomes, ownerOutcome{p: p, selected: selected})
+	}
+	if len(owners) > 0 && n > 0 {
+		walk(0, nil, map[string]bool{}, 1)
+	} else {
+		outcomes = append(outcomes, ownerOutcome{p: 1})
+	}
+	return outcomes
+}
+
+// ownerIDs возвращает id всех владельцев путей
+func ownerIDs(owners map[string][]pathOwner) []string {
+	var ids []string
+	for _, list := range owners {
+		for _, o := range list {
+			if !slices.Contains(ids, o.user.ID) {
+				ids = append(ids, o.user.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// pickCodeOwners выбирает до n владельцев затронутых путей из owners, стараясь покрыть как можно больше путей.
+// Владельцы, достигшие лимита открытых ревью по loads, пропускаются
+func pickCodeOwners(rng *rand.Rand, paths []string, owners map[string][]pathOwner, loads reviewerLoads, n int) []domain.ReviewerMatch {
+	if len(owners) == 0 || n <= 0 {
+		return nil
+	}
+
+	selected := make(map[string]bool)
+	covered := make(map[string]bool)
+	matches := make([]domain.ReviewerMatch, 0, n)
+	for _, p := range paths {
+		if len(matches) == n {
+			break
+		}
+		if covered[p] {
+			continue
+		}
+		var options []pathOwner
+		for _, o := range owners[p] {
+			if !selected[o.user.ID] && loads[o.user.ID].HasCapacity() {
+				options = append(options, o)
+			}
+		}
+		if len(options) == 0 {
+			continue
+		}
+		chosen := options[rng.IntN(len(options))]
+		selected[chosen.user.ID] = true
+		rule := chosen.rule
+		matches = append(matches, domain.ReviewerMatch{
+			UserID:      chosen.user.ID,
+			Reason:      domain.MatchReasonCodeOwner,
+			MatchedTags: []string{},
+			Rule:        &rule,
+			Explanation: &domain.AssignmentExplanation{PoolSize: len(options), OpenReviews: loads[chosen.user.ID].OpenReviews},
+		})
+		for _, q := range paths {
+			for _, o := range owners[q] {
+				if o.user.ID == chosen.user.ID {
+					covered[q] = true
+				}
+			}
+		}
+	}
+	return matches
+}
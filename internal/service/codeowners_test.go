@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/codeowners"
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_CodeOwners(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	_, err = svc.CreateTeam(ctx, "co-app")
+	require.NoError(t, err)
+	_, err = svc.CreateTeam(ctx, "co-db")
+	require.NoError(t, err)
+	for _, id := range []string{"coa", "coa1", "coa2", "coa3"} {
+		_, err = svc.CreateUser(ctx, id, id, "co-app", true)
+		require.NoError(t, err)
+	}
+	_, err = svc.CreateUser(ctx, "cod1", "DBA", "co-db", true)
+	require.NoError(t, err)
+
+	t.Run("SetCodeOwners_UnknownOwner", func(t *testing.T) {
+		_, _, err := svc.SetCodeOwners(ctx, "co-db", "/migrations/ @nobody\n")
+
+		assert.ErrorIs(t, err, domain.ErrUnknownOwner)
+	})
+
+	t.Run("SetCodeOwners_InvalidSyntax", func(t *testing.T) {
+		_, _, err := svc.SetCodeOwners(ctx, "co-db", "/migrations/ dba@example.com\n")
+
+		assert.ErrorIs(t, err, codeowners.ErrInvalidSyntax)
+	})
+
+	t.Run("CreatePR_PicksOwnersFromOtherTeam", func(t *testing.T) {
+		_, rules, err := svc.SetCodeOwners(ctx, "co-db", "# db\n/migrations/ @acme/co-db\n")
+		require.NoError(t, err)
+		require.Equal(t, 1, rules)
+
+		pr, err := svc.CreatePR(ctx, "pr-co", "T", "coa", CreatePROptions{
+			ChangedPaths: []string{"/migrations/007.sql", "cmd/app/main.go"},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, pr.Reviewers, 2)
+		assert.Contains(t, pr.Reviewers, "cod1")
+
+		got, err := svc.GetPR(ctx, "pr-co")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"migrations/007.sql", "cmd/app/main.go"}, got.ChangedPaths)
+		require.Len(t, got.Matches, 2)
+		for _, m := range got.Matches {
+			if m.UserID != "cod1" {
+				assert.Equal(t, domain.MatchReasonRandom, m.Reason)
+				continue
+			}
+			assert.Equal(t, domain.MatchReasonCodeOwner, m.Reason)
+			require.NotNil(t, m.Rule)
+			assert.Equal(t, domain.OwnershipRule{TeamName: "co-db", Line: 2, Pattern: "/migrations/", Path: "migrations/007.sql"}, *m.Rule)
+		}
+	})
+}
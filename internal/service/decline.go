@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"reviewer/internal/domain"
+)
+
+// DeclineReview снимает ревьюера с PR по его собственному отказу, записывает отказ в историю
+// и подбирает замену так же, как ReassignReviewer. Отказавшийся исключается из ревью PR.
+// Если замены нет, отказ всё равно принимается, а replacement равен nil
+func (s *Service) DeclineReview(ctx context.Context, prID, userID string, reason domain.DeclineReason) (result domain.PullRequest, replacement *domain.User, err error) {
+	err = s.runInTx(ctx, func(ctxTx context.Context) error {
+		pr, err := s.repo.GetPRForUpdate(ctxTx, prID)
+		if err != nil {
+			return err
+		}
+		if pr.Status == domain.PRStatusMerged {
+			return domain.ErrPRMerged
+		}
+		if !slices.Contains(pr.Reviewers, userID) {
+			return domain.ErrNotAssigned
+		}
+
+		if err := s.repo.RemoveReviewer(ctxTx, prID, userID); err != nil {
+			return err
+		}
+		if err := s.repo.RecordDecline(ctxTx, prID, userID, reason); err != nil {
+			return err
+		}
+		if err := s.enqueueNotifications(ctxTx, domain.Notification{
+			UserID:        pr.AuthorID,
+			Kind:          domain.NotificationReviewDeclined,
+			PullRequestID: prID,
+			Text:          fmt.Sprintf("%s declined to review %s: %s", userID, prID, reason),
+		}); err != nil {
+			return err
+		}
+		if err := s.repo.AddPRExclusions(ctxTx, prID, []string{userID}); err != nil {
+			return err
+		}
+		pr.ExcludedReviewers = append(pr.ExcludedReviewers, userID)
+		pr.Reviewers = slices.DeleteFunc(slices.Clone(pr.Reviewers), func(id string) bool { return id == userID })
+
+		sel, err := s.fillReviewers(ctxTx, pr, 1, "", nil)
+		if err != nil {
+			return err
+		}
+		if len(sel.matches) > 0 {
+			u, err := s.repo.GetUser(ctxTx, sel.matches[0].UserID)
+			if err != nil {
+				return err
+			}
+			replacement = &u
+		}
+
+		result, err = s.repo.GetPR(ctxTx, prID)
+		result.Violations = sel.violations
+		result.RuleTrace = sel.trace
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, nil, err
+	}
+	return result, replacement, nil
+}
+
+// DeclineStats возвращает отказы от ревью по пользователям и по командам; teamName ограничивает статистику командой
+func (s *Service) DeclineStats(ctx context.Context, teamName string) (users, teams []domain.DeclineStats, err error) {
+	if teamName != "" {
+		if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+			return nil, nil, err
+		}
+	}
+	users, err = s.repo.GetDeclineStats(ctx, teamName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teams = make([]domain.DeclineStats, 0)
+	byTeam := make(map[string]int)
+	for i := range users {
+		u := &users[i]
+		u.DeclineRate = declineRate(u.Declines, u.Assignments)
+		idx, ok := byTeam[u.TeamName]
+		if !ok {
+			idx = len(teams)
+			byTeam[u.TeamName] = idx
+			teams = append(teams, domain.DeclineStats{TeamName: u.TeamName, ByReason: map[domain.DeclineReason]int64{}})
+		}
+		t := &teams[idx]
+		t.Assignments += u.Assignments
+		t.Declines += u.Declines
+		for r, n := range u.ByReason {
+			t.ByReason[r] += n
+		}
+	}
+	for i := range teams {
+		teams[i].DeclineRate = declineRate(teams[i].Declines, teams[i].Assignments)
+	}
+	slices.SortFunc(teams, func(a, b domain.DeclineStats) int { return strings.Compare(a.TeamName, b.TeamName) })
+	return users, teams, nil
+}
+
+func declineRate(declines, assignments int64) float64 {
+	if assignments == 0 {
+		return 0
+	}
+	return float64(declines) / float64(assignments)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_Decline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	tName := "decline-team"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	for _, id := range []string{"dc_author", "dc_busy", "dc_keep", "dc_next"} {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+	}
+	_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "dc-pr", Title: "T", AuthorID: "dc_author", TeamName: tName, Status: domain.PRStatusOpen})
+	require.NoError(t, err)
+	require.NoError(t, repo.AddReviewers(ctx, "dc-pr", []string{"dc_busy", "dc_keep"}))
+
+	t.Run("DeclineReview_Reassigns", func(t *testing.T) {
+		pr, replacement, err := svc.DeclineReview(ctx, "dc-pr", "dc_busy", domain.DeclineReasonBusy)
+
+		require.NoError(t, err)
+		require.NotNil(t, replacement)
+		assert.Equal(t, "dc_next", replacement.ID)
+		assert.ElementsMatch(t, []string{"dc_keep", "dc_next"}, pr.Reviewers)
+		assert.Contains(t, pr.ExcludedReviewers, "dc_busy")
+		history, err := svc.GetAssignmentHistory(ctx, "dc-pr")
+		require.NoError(t, err)
+		var declined []domain.AssignmentRecord
+		for _, h := range history {
+			if h.Action == domain.AssignmentActionDeclined {
+				declined = append(declined, h)
+			}
+		}
+		require.Len(t, declined, 1)
+		assert.Equal(t, "dc_busy", declined[0].ReviewerID)
+		assert.Equal(t, domain.DeclineReasonBusy, declined[0].DeclineReason)
+	})
+
+	t.Run("DeclineReview_NoReplacement", func(t *testing.T) {
+		pr, replacement, err := svc.DeclineReview(ctx, "dc-pr", "dc_keep", domain.DeclineReasonLacksContext)
+
+		require.NoError(t, err)
+		assert.Nil(t, replacement)
+		assert.Equal(t, []string{"dc_next"}, pr.Reviewers)
+	})
+
+	t.Run("DeclineReview_NotAssigned", func(t *testing.T) {
+		_, _, err := svc.DeclineReview(ctx, "dc-pr", "dc_busy", domain.DeclineReasonBusy)
+
+		assert.ErrorIs(t, err, domain.ErrNotAssigned)
+	})
+
+	t.Run("DeclineStats", func(t *testing.T) {
+		users, teams, err := svc.DeclineStats(ctx, tName)
+
+		require.NoError(t, err)
+		byUser := make(map[string]domain.DeclineStats)
+		for _, u := range users {
+			byUser[u.UserID] = u
+		}
+		assert.Equal(t, int64(1), byUser["dc_busy"].Assignments)
+		assert.Equal(t, int64(1), byUser["dc_busy"].Declines)
+		assert.InDelta(t, 1.0, byUser["dc_busy"].DeclineRate, 1e-9)
+		assert.Equal(t, int64(0), byUser["dc_next"].Declines)
+		require.Len(t, teams, 1)
+		assert.Equal(t, int64(3), teams[0].Assignments)
+		assert.Equal(t, int64(2), teams[0].Declines)
+		assert.Equal(t, map[domain.DeclineReason]int64{domain.DeclineReasonBusy: 1, domain.DeclineReasonLacksContext: 1}, teams[0].ByReason)
+	})
+}
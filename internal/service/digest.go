@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"reviewer/internal/digest"
+	"reviewer/internal/domain"
+)
+
+// DefaultDigestHour — местный час, начиная с которого пользователю отправляется сводка
+const DefaultDigestHour = 9
+
+// digestPeriod — за какой период в сводку попадают слитые PR
+const digestPeriod = 24 * time.Hour
+
+// GetDigest собирает сводку пользователя на текущий момент, не отправляя её
+func (s *Service) GetDigest(ctx context.Context, userID string) (domain.Digest, error) {
+	prefs, err := s.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return domain.Digest{}, err
+	}
+	return s.buildDigest(ctx, prefs)
+}
+
+// SendDigests отправляет ежедневную сводку пользователям в режиме сводки, у которых уже
+// наступил DigestHour по их времени, а сводка за сегодня ещё не ушла. Вместе со сводкой
+// отправленными считаются накопленные уведомления. Сводка в тихие часы и неудачная отправка
+// повторяются при следующем запуске; пустая сводка не отправляется
+func (s *Service) SendDigests(ctx context.Context) (*domain.DeliveryResult, error) {
+	result := &domain.DeliveryResult{Errors: []string{}}
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		recipients, err := s.repo.ListDigestRecipients(ctxTx)
+		if err != nil {
+			return fmt.Errorf("listing digest recipients: %w", err)
+		}
+		now := s.now()
+		for _, rec := range recipients {
+			if !s.digestDue(rec, now) {
+				continue
+			}
+			if rec.Preferences.InQuietHours(now) {
+				result.Deferred++
+				continue
+			}
+			d, err := s.buildDigest(ctxTx, rec.Preferences)
+			if err != nil {
+				return fmt.Errorf("building digest for %s: %w", rec.Preferences.UserID, err)
+			}
+			if !d.Empty() {
+				n, err := digestNotification(d)
+				if err != nil {
+					return err
+				}
+				if err := s.notifier.Notify(ctxTx, n, rec.Preferences); err != nil {
+					result.Failed++
+					result.Errors = append(result.Errors, fmt.Sprintf("digest to %s: %v", d.UserID, err))
+					continue
+				}
+				ids := make([]int64, len(d.Notifications))
+				for i, pending := range d.Notifications {
+					ids[i] = pending.ID
+				}
+				if err := s.repo.MarkNotificationsSent(ctxTx, ids, now); err != nil {
+					return fmt.Errorf("marking notifications sent: %w", err)
+				}
+				result.Sent++
+			}
+			if err := s.repo.MarkDigestSent(ctxTx, rec.Preferences.UserID, now); err != nil {
+				return fmt.Errorf("marking digest sent: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Service) digestDue(rec domain.DigestRecipient, now time.Time) bool {
+	local := now.In(rec.Preferences.Location())
+	if local.Hour() < s.digestHour {
+		return false
+	}
+	if rec.LastSentAt == nil {
+		return true
+	}
+	y, m, d := rec.LastSentAt.In(local.Location()).Date()
+	ny, nm, nd := local.Date()
+	return y != ny || m != nm || d != nd
+}
+
+// buildDigest собирает сводку; накопленные уведомления попадают в неё только в режиме сводки,
+// иначе они отправляются сразу
+func (s *Service) buildDigest(ctx context.Context, prefs domain.NotificationPreferences) (domain.Digest, error) {
+	loc := prefs.Location()
+	now := s.now().In(loc)
+	d := domain.Digest{
+		UserID:        prefs.UserID,
+		GeneratedAt:   now,
+		Since:         now.Add(-digestPeriod),
+		OpenReviews:   []domain.DigestReview{},
+		Notifications: []domain.Notification{},
+	}
+
+	reviews, err := s.repo.ListPRsByReviewer(ctx, prefs.UserID)
+	if err != nil {
+		return domain.Digest{}, fmt.Errorf("listing reviews: %w", err)
+	}
+	for _, pr := range reviews {
+		if pr.Status != domain.PRStatusOpen {
+			continue
+		}
+		review := domain.DigestReview{PullRequestShort: pr}
+		if pr.AssignedAt != nil {
+			assignedAt := pr.AssignedAt.In(loc)
+			review.AssignedAt = &assignedAt
+			review.AgeHours = int(now.Sub(assignedAt).Hours())
+		}
+		d.OpenReviews = append(d.OpenReviews, review)
+	}
+
+	if d.AwaitingReview, err = s.repo.ListAwaitingPRs(ctx, prefs.UserID); err != nil {
+		return domain.Digest{}, fmt.Errorf("listing awaiting pull requests: %w", err)
+	}
+	for i := range d.AwaitingReview {
+		d.AwaitingReview[i].CreatedAt = d.AwaitingReview[i].CreatedAt.In(loc)
+	}
+	if d.RecentlyMerged, err = s.repo.ListMergedPRs(ctx, prefs.UserID, d.Since); err != nil {
+		return domain.Digest{}, fmt.Errorf("listing merged pull requests: %w", err)
+	}
+	for i := range d.RecentlyMerged {
+		d.RecentlyMerged[i].MergedAt = d.RecentlyMerged[i].MergedAt.In(loc)
+	}
+
+	if prefs.Delivery == domain.NotificationDeliveryDigest {
+		if d.Notifications, err = s.repo.ListDigestNotifications(ctx, prefs.UserID); err != nil {
+			return domain.Digest{}, fmt.Errorf("listing digest notifications: %w", err)
+		}
+		for i := range d.Notifications {
+			d.Notifications[i].CreatedAt = d.Notifications[i].CreatedAt.In(loc)
+		}
+	}
+	return d, nil
+}
+
+func digestNotification(d domain.Digest) (domain.Notification, error) {
+	text, err := digest.Markdown(d)
+	if err != nil {
+		return domain.Notification{}, err
+	}
+	html, err := digest.HTML(d)
+	if err != nil {
+		return domain.Notification{}, err
+	}
+	return domain.Notification{
+		UserID:    d.UserID,
+		Kind:      domain.NotificationDigest,
+		Text:      text,
+		HTML:      html,
+		CreatedAt: d.GeneratedAt,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_Digest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+
+	// время слияния ставит база, поэтому часы сервиса идут от настоящего времени
+	now := time.Now().Add(2*time.Hour + 30*time.Minute)
+	notifier := &recordingNotifier{}
+	svc := New(repo, WithClock(func() time.Time { return now }), WithNotifier(notifier), WithDigestHour(0))
+
+	tName := "digest-team"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	for _, id := range []string{"dg_author", "dg_user", "dg_other"} {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+	}
+	_, err = svc.SetNotificationPreferences(ctx, domain.NotificationPreferences{
+		UserID:   "dg_user",
+		Channel:  domain.NotificationChannelSlack,
+		Delivery: domain.NotificationDeliveryDigest,
+		Timezone: "UTC",
+	})
+	require.NoError(t, err)
+
+	_, err = svc.CreatePR(ctx, "dg-review", "Review me", "dg_author", CreatePROptions{})
+	require.NoError(t, err)
+	_, err = svc.CreatePR(ctx, "dg-own", "Mine", "dg_user", CreatePROptions{})
+	require.NoError(t, err)
+	_, err = svc.SubmitReview(ctx, "dg-own", "dg_author", domain.ReviewDecisionApproved)
+	require.NoError(t, err)
+	_, err = svc.CreatePR(ctx, "dg-merged", "Done", "dg_user", CreatePROptions{})
+	require.NoError(t, err)
+	_, err = svc.MergePR(ctx, "dg-merged")
+	require.NoError(t, err)
+
+	t.Run("GetDigest", func(t *testing.T) {
+		d, err := svc.GetDigest(ctx, "dg_user")
+
+		require.NoError(t, err)
+		require.Len(t, d.OpenReviews, 1)
+		assert.Equal(t, "dg-review", d.OpenReviews[0].ID)
+		assert.Equal(t, 2, d.OpenReviews[0].AgeHours)
+		require.Len(t, d.AwaitingReview, 1)
+		assert.Equal(t, "dg-own", d.AwaitingReview[0].ID)
+		assert.Equal(t, []string{"dg_other"}, d.AwaitingReview[0].AwaitingReviewers)
+		require.Len(t, d.RecentlyMerged, 1)
+		assert.Equal(t, "dg-merged", d.RecentlyMerged[0].ID)
+		require.Len(t, d.Notifications, 1)
+		assert.Equal(t, domain.NotificationReviewAssigned, d.Notifications[0].Kind)
+	})
+
+	t.Run("GetDigest_UnknownUser", func(t *testing.T) {
+		_, err := svc.GetDigest(ctx, "dg_missing")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("SendDigests", func(t *testing.T) {
+		result, err := svc.SendDigests(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Sent)
+		digests := notifier.ofKind(domain.NotificationDigest)
+		require.Len(t, digests, 1)
+		assert.Equal(t, "dg_user", digests[0].UserID)
+		assert.Contains(t, digests[0].Text, "**dg-review** Review me by dg_author, waiting 2h")
+		assert.Contains(t, digests[0].HTML, "<b>dg-own</b>")
+
+		d, err := svc.GetDigest(ctx, "dg_user")
+		require.NoError(t, err)
+		assert.Empty(t, d.Notifications)
+	})
+
+	t.Run("SendDigests_OncePerDay", func(t *testing.T) {
+		result, err := svc.SendDigests(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Sent)
+		assert.Len(t, notifier.ofKind(domain.NotificationDigest), 1)
+
+		now = now.Add(24 * time.Hour)
+		result, err = svc.SendDigests(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Sent)
+		assert.Len(t, notifier.ofKind(domain.NotificationDigest), 2)
+	})
+}
+
+func TestService_DigestDue(t *testing.T) {
+	svc := New(nil, WithDigestHour(9))
+	prefs := domain.NotificationPreferences{Timezone: "Europe/Moscow"}
+	// 09:30 по Москве
+	now := time.Date(2026, 3, 2, 6, 30, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	today := now.Add(-10 * time.Minute)
+
+	assert.True(t, svc.digestDue(domain.DigestRecipient{Preferences: prefs}, now))
+	assert.True(t, svc.digestDue(domain.DigestRecipient{Preferences: prefs, LastSentAt: &yesterday}, now))
+	assert.False(t, svc.digestDue(domain.DigestRecipient{Preferences: prefs, LastSentAt: &today}, now))
+	assert.False(t, svc.digestDue(domain.DigestRecipient{Preferences: prefs}, now.Add(-time.Hour)))
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"reviewer/internal/domain"
+)
+
+func (s *Service) CreateReviewExclusion(ctx context.Context, e domain.ReviewExclusion) (domain.ReviewExclusion, error) {
+	return s.repo.CreateReviewExclusion(ctx, e)
+}
+
+func (s *Service) DeleteReviewExclusion(ctx context.Context, id int64) error {
+	return s.repo.DeleteReviewExclusion(ctx, id)
+}
+
+func (s *Service) ListReviewExclusions(ctx context.Context, userID string) ([]domain.ReviewExclusion, error) {
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListReviewExclusions(ctx, userID)
+}
+
+// SetUserManager назначает пользователю руководителя; руководители автора не ревьюят его PR.
+// Пустой managerID снимает руководителя
+func (s *Service) SetUserManager(ctx context.Context, id string, managerID *string) (domain.User, error) {
+	var updated domain.User
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		for next := managerID; next != nil; {
+			if *next == id {
+				return domain.ErrManagerCycle
+			}
+			manager, err := s.repo.GetUser(ctxTx, *next)
+			if err != nil {
+				return fmt.Errorf("getting manager: %w", err)
+			}
+			next = manager.ManagerID
+		}
+		var err error
+		updated, err = s.repo.SetUserManager(ctxTx, id, managerID)
+		return err
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	return updated, nil
+}
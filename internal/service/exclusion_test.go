@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_Exclusion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	tName := "excl-team"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	for _, id := range []string{"ex_author", "ex_lead", "ex_head", "ex_pair", "ex_explicit", "ex_ok"} {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+	}
+	lead, head := "ex_lead", "ex_head"
+	_, err = svc.SetUserManager(ctx, "ex_author", &lead)
+	require.NoError(t, err)
+	_, err = svc.SetUserManager(ctx, "ex_lead", &head)
+	require.NoError(t, err)
+	_, err = svc.CreateReviewExclusion(ctx, domain.ReviewExclusion{ReviewerID: "ex_pair", AuthorID: "ex_author", Reason: "pairing"})
+	require.NoError(t, err)
+
+	t.Run("SetUserManager_Cycle", func(t *testing.T) {
+		author := "ex_author"
+
+		_, err := svc.SetUserManager(ctx, "ex_head", &author)
+
+		assert.ErrorIs(t, err, domain.ErrManagerCycle)
+	})
+
+	t.Run("CreatePR_RespectsExclusions", func(t *testing.T) {
+		pr, err := svc.CreatePR(ctx, "pr-excl", "T", "ex_author", CreatePROptions{ExcludeReviewers: []string{"ex_explicit"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ex_ok"}, pr.Reviewers)
+		assert.Equal(t, []string{"ex_explicit"}, pr.ExcludedReviewers)
+	})
+
+	t.Run("ReassignReviewer_KeepsPRExclusions", func(t *testing.T) {
+		_, _, err := svc.ReassignReviewer(ctx, "pr-excl", "ex_ok", ReassignOptions{})
+
+		assert.ErrorIs(t, err, domain.ErrNoCandidates)
+	})
+
+	t.Run("ReassignReviewer_ExcludeList", func(t *testing.T) {
+		_, err := svc.CreateUser(ctx, "ex_new", "New", tName, true)
+		require.NoError(t, err)
+		pr, err := svc.GetPR(ctx, "pr-excl")
+		require.NoError(t, err)
+		require.Contains(t, pr.Reviewers, "ex_new")
+		_, err = svc.CreateUser(ctx, "ex_late", "Late", tName, true)
+		require.NoError(t, err)
+
+		_, _, err = svc.ReassignReviewer(ctx, "pr-excl", "ex_ok", ReassignOptions{ExcludeReviewers: []string{"ex_late"}})
+
+		assert.ErrorIs(t, err, domain.ErrNoCandidates)
+	})
+}
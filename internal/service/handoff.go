@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"reviewer/internal/domain"
+)
+
+// HandoffAuto — цель передачи, при которой замены подбираются автоматически
+const HandoffAuto = "auto"
+
+// HandoffReviews передаёт все открытые ревью пользователя явно указанному target или, если
+// target == HandoffAuto, заменам, подобранным по правилам ReassignReviewer. Передача выполняется
+// в одной транзакции; ревью, для которых замены нет, остаются за пользователем и отмечаются в результате
+func (s *Service) HandoffReviews(ctx context.Context, userID, target string) (*domain.HandoffResult, error) {
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	var opts ReassignOptions
+	if target != HandoffAuto {
+		if target == userID {
+			return nil, fmt.Errorf("%w: cannot hand off reviews to the same user", domain.ErrNotEligible)
+		}
+		if _, err := s.repo.GetUser(ctx, target); err != nil {
+			return nil, err
+		}
+		opts.NewReviewerID = target
+	}
+
+	result := &domain.HandoffResult{UserID: userID, Outcomes: []domain.HandoffOutcome{}}
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		prs, err := s.repo.ListPRsByReviewer(ctxTx, userID)
+		if err != nil {
+			return fmt.Errorf("listing reviews: %w", err)
+		}
+		for _, pr := range prs {
+			if pr.Status != domain.PRStatusOpen {
+				continue
+			}
+			outcome := domain.HandoffOutcome{PullRequestID: pr.ID, Status: domain.HandoffReassigned}
+			_, newReviewer, err := s.reassignReviewer(ctxTx, pr.ID, userID, opts)
+			switch {
+			case err == nil:
+				outcome.NewReviewerID = newReviewer.ID
+				result.Reassigned++
+				if err := s.enqueueNotifications(ctxTx, reassignedNotification(pr.ID, userID, newReviewer.ID)); err != nil {
+					return err
+				}
+			case isReassignFailure(err):
+				outcome.Status = domain.HandoffFailed
+				outcome.Error = err.Error()
+				result.Failed++
+			default:
+				return fmt.Errorf("reassigning %s: %w", pr.ID, err)
+			}
+			result.Outcomes = append(result.Outcomes, outcome)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
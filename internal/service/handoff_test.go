@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_Handoff(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	tName := "handoff-team"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	for _, id := range []string{"ho_author", "ho_leaving", "ho_other", "ho_spare"} {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+	}
+	for id, reviewers := range map[string][]string{
+		"ho-pr-a": {"ho_leaving", "ho_other"},
+		"ho-pr-b": {"ho_leaving", "ho_spare"},
+	} {
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: id, Title: "T", AuthorID: "ho_author", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		require.NoError(t, repo.AddReviewers(ctx, id, reviewers))
+	}
+
+	t.Run("HandoffReviews_ExplicitTarget", func(t *testing.T) {
+		result, err := svc.HandoffReviews(ctx, "ho_leaving", "ho_spare")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Reassigned)
+		assert.Equal(t, 1, result.Failed)
+		outcomes := make(map[string]domain.HandoffOutcome)
+		for _, o := range result.Outcomes {
+			outcomes[o.PullRequestID] = o
+		}
+		assert.Equal(t, domain.HandoffReassigned, outcomes["ho-pr-a"].Status)
+		assert.Equal(t, "ho_spare", outcomes["ho-pr-a"].NewReviewerID)
+		assert.Equal(t, domain.HandoffFailed, outcomes["ho-pr-b"].Status)
+		assert.NotEmpty(t, outcomes["ho-pr-b"].Error)
+		pr, err := svc.GetPR(ctx, "ho-pr-b")
+		require.NoError(t, err)
+		assert.Contains(t, pr.Reviewers, "ho_leaving")
+	})
+
+	t.Run("HandoffReviews_Auto", func(t *testing.T) {
+		result, err := svc.HandoffReviews(ctx, "ho_leaving", HandoffAuto)
+
+		require.NoError(t, err)
+		require.Len(t, result.Outcomes, 1)
+		assert.Equal(t, domain.HandoffReassigned, result.Outcomes[0].Status)
+		assert.Equal(t, "ho_other", result.Outcomes[0].NewReviewerID)
+		prs, err := svc.ListPRsByReviewer(ctx, "ho_leaving")
+		require.NoError(t, err)
+		assert.Empty(t, prs)
+	})
+
+	t.Run("HandoffReviews_SameUser", func(t *testing.T) {
+		_, err := svc.HandoffReviews(ctx, "ho_other", "ho_other")
+
+		assert.ErrorIs(t, err, domain.ErrNotEligible)
+	})
+
+	t.Run("HandoffReviews_UnknownUser", func(t *testing.T) {
+		_, err := svc.HandoffReviews(ctx, "ho_missing", HandoffAuto)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
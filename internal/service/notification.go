@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"reviewer/internal/domain"
+)
+
+// maxNotificationAttempts — после стольких неудачных попыток уведомление больше не отправляется
+const maxNotificationAttempts = 5
+
+func (s *Service) GetNotificationPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+	prefs, err := s.repo.GetNotificationPreferences(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultNotificationPreferences(userID), nil
+	}
+	return prefs, err
+}
+
+func (s *Service) SetNotificationPreferences(ctx context.Context, prefs domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	return s.repo.SetNotificationPreferences(ctx, prefs)
+}
+
+// DeliverNotifications отправляет накопленные уведомления получателям без режима сводки.
+// Уведомления, попавшие в тихие часы получателя, остаются до следующей отправки; неудачные
+// попытки записываются и повторяются, пока их меньше maxNotificationAttempts
+func (s *Service) DeliverNotifications(ctx context.Context) (*domain.DeliveryResult, error) {
+	result := &domain.DeliveryResult{Errors: []string{}}
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		pending, err := s.repo.ListPendingNotifications(ctxTx, maxNotificationAttempts)
+		if err != nil {
+			return fmt.Errorf("listing pending notifications: %w", err)
+		}
+		now := s.now()
+		sent := make([]int64, 0, len(pending))
+		for _, n := range pending {
+			if n.Preferences.InQuietHours(now) {
+				result.Deferred++
+				continue
+			}
+			if err := s.notifier.Notify(ctxTx, n.Notification, n.Preferences); err != nil {
+				if err := s.repo.RecordNotificationFailure(ctxTx, n.ID, err.Error()); err != nil {
+					return fmt.Errorf("recording notification failure: %w", err)
+				}
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s to %s: %v", n.Kind, n.UserID, err))
+				continue
+			}
+			sent = append(sent, n.ID)
+		}
+		if err := s.repo.MarkNotificationsSent(ctxTx, sent, now); err != nil {
+			return fmt.Errorf("marking notifications sent: %w", err)
+		}
+		result.Sent = len(sent)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// enqueueNotifications ставит уведомления в очередь в текущей транзакции, так что они
+// уходят только если изменение, о котором сообщают, зафиксировано
+func (s *Service) enqueueNotifications(ctx context.Context, notifications ...domain.Notification) error {
+	if err := s.repo.EnqueueNotifications(ctx, notifications); err != nil {
+		return fmt.Errorf("enqueueing notifications: %w", err)
+	}
+	return nil
+}
+
+func assignedNotification(prID, userID string) domain.Notification {
+	return domain.Notification{
+		UserID:        userID,
+		Kind:          domain.NotificationReviewAssigned,
+		PullRequestID: prID,
+		Text:          fmt.Sprintf("You were assigned to review %s", prID),
+	}
+}
+
+func reassignedNotification(prID, oldReviewerID, newReviewerID string) domain.Notification {
+	return domain.Notification{
+		UserID:        oldReviewerID,
+		Kind:          domain.NotificationReviewReassigned,
+		PullRequestID: prID,
+		Text:          fmt.Sprintf("Your review of %s was reassigned to %s", prID, newReviewerID),
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+type recordingNotifier struct {
+	sent []domain.Notification
+	fail bool
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, msg domain.Notification, _ domain.NotificationPreferences) error {
+	if n.fail {
+		return errors.New("channel is down")
+	}
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+func (n *recordingNotifier) ofKind(kind domain.NotificationKind) []domain.Notification {
+	var found []domain.Notification
+	for _, msg := range n.sent {
+		if msg.Kind == kind {
+			found = append(found, msg)
+		}
+	}
+	return found
+}
+
+func TestService_Notifications(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+
+	// 12:00 UTC
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	notifier := &recordingNotifier{}
+	svc := New(repo, WithClock(func() time.Time { return now }), WithNotifier(notifier))
+
+	tName := "notify-team"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	for _, id := range []string{"nt_author", "nt_quiet", "nt_digest"} {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+	}
+
+	t.Run("GetNotificationPreferences_Default", func(t *testing.T) {
+		prefs, err := svc.GetNotificationPreferences(ctx, "nt_quiet")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.DefaultNotificationPreferences("nt_quiet"), prefs)
+	})
+
+	t.Run("SetNotificationPreferences", func(t *testing.T) {
+		prefs, err := svc.SetNotificationPreferences(ctx, domain.NotificationPreferences{
+			UserID:     "nt_quiet",
+			Channel:    domain.NotificationChannelEmail,
+			Address:    "quiet@example.com",
+			Delivery:   domain.NotificationDeliveryImmediate,
+			QuietHours: &domain.QuietHours{Start: 11, End: 13},
+			Timezone:   "UTC",
+		})
+		require.NoError(t, err)
+		_, err = svc.SetNotificationPreferences(ctx, domain.NotificationPreferences{
+			UserID:   "nt_digest",
+			Channel:  domain.NotificationChannelSlack,
+			Delivery: domain.NotificationDeliveryDigest,
+			Timezone: "Europe/Moscow",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, &domain.QuietHours{Start: 11, End: 13}, prefs.QuietHours)
+		saved, err := svc.GetNotificationPreferences(ctx, "nt_quiet")
+		require.NoError(t, err)
+		assert.Equal(t, prefs, saved)
+	})
+
+	t.Run("SetNotificationPreferences_UnknownUser", func(t *testing.T) {
+		_, err := svc.SetNotificationPreferences(ctx, domain.DefaultNotificationPreferences("nt_missing"))
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("DeliverNotifications", func(t *testing.T) {
+		_, err := svc.CreatePR(ctx, "nt-pr", "T", "nt_author", CreatePROptions{})
+		require.NoError(t, err)
+
+		result, err := svc.DeliverNotifications(ctx)
+
+		require.NoError(t, err)
+		// nt_quiet в тихих часах, nt_digest ждёт сводки
+		assert.Equal(t, 0, result.Sent)
+		assert.Equal(t, 1, result.Deferred)
+		assert.Empty(t, notifier.sent)
+
+		now = now.Add(2 * time.Hour)
+		result, err = svc.DeliverNotifications(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Sent)
+		require.Len(t, notifier.sent, 1)
+		assert.Equal(t, domain.NotificationReviewAssigned, notifier.sent[0].Kind)
+		assert.Equal(t, "nt_quiet", notifier.sent[0].UserID)
+	})
+
+	t.Run("DeliverNotifications_Failure", func(t *testing.T) {
+		pr, err := svc.GetPR(ctx, "nt-pr")
+		require.NoError(t, err)
+		_, _, err = svc.DeclineReview(ctx, "nt-pr", pr.Reviewers[0], domain.DeclineReasonBusy)
+		require.NoError(t, err)
+		notifier.fail = true
+
+		result, err := svc.DeliverNotifications(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+		require.Len(t, result.Errors, 1)
+
+		notifier.fail = false
+		result, err = svc.DeliverNotifications(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Sent)
+		assert.Len(t, notifier.ofKind(domain.NotificationReviewDeclined), 1)
+	})
+}
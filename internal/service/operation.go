@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"reviewer/internal/domain"
+)
+
+func (s *Service) UndoOperation(ctx context.Context, id int64) (*domain.UndoResult, error) {
+	var result *domain.UndoResult
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		op, err := s.repo.GetOperationForUpdate(ctxTx, id)
+		if err != nil {
+			return err
+		}
+		result, err = s.undoOperation(ctxTx, op)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// undoOperation должна вызываться внутри транзакции, заблокировавшей операцию
+func (s *Service) undoOperation(ctx context.Context, op domain.Operation) (*domain.UndoResult, error) {
+	if op.UndoneAt != nil {
+		return nil, domain.ErrAlreadyUndone
+	}
+
+	reactivatedUsers, err := s.repo.ActivateUsers(ctx, op.UserIDs)
+	if err != nil {
+		return nil, fmt.Errorf("activating users: %w", err)
+	}
+
+	restored, err := s.repo.RestoreRemovedReviews(ctx, op)
+	if err != nil {
+		return nil, fmt.Errorf("restoring reviews: %w", err)
+	}
+
+	if _, err := s.repo.MarkOperationUndone(ctx, op.ID); err != nil {
+		return nil, fmt.Errorf("marking operation undone: %w", err)
+	}
+
+	backfilled, err := s.backfillTeam(ctx, op.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("backfilling team: %w", err)
+	}
+
+	restoredSet := make(map[domain.ReviewAssignment]bool, len(restored))
+	for _, rv := range restored {
+		restoredSet[rv] = true
+	}
+	skipped := make([]domain.ReviewAssignment, 0)
+	for _, rv := range op.RemovedReviews {
+		if !restoredSet[rv] {
+			skipped = append(skipped, rv)
+		}
+	}
+
+	return &domain.UndoResult{
+		OperationID:      op.ID,
+		ReactivatedUsers: reactivatedUsers,
+		RestoredReviews:  restored,
+		SkippedReviews:   skipped,
+		Backfilled:       backfilled,
+	}, nil
+}
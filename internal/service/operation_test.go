@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_Operation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	t.Run("UndoOperation_RestoresUsersAndReviews", func(t *testing.T) {
+		tName := "undo-team"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "un_a", "A", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "un_r1", "R1", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "un_r2", "R2", tName, true)
+		require.NoError(t, err)
+		pr, err := svc.CreatePR(ctx, "pr-undo", "T", "un_a", CreatePROptions{})
+		require.NoError(t, err)
+		deact, err := svc.DeactivateTeamAndRemoveReviews(ctx, tName, DeactivationOptions{})
+		require.NoError(t, err)
+		require.NotZero(t, deact.OperationID)
+
+		res, err := svc.UndoOperation(ctx, deact.OperationID)
+
+		require.NoError(t, err)
+		assert.Len(t, res.ReactivatedUsers, 3)
+		assert.Len(t, res.RestoredReviews, 2)
+		assert.Empty(t, res.SkippedReviews)
+		restoredPR, _ := repo.GetPR(ctx, pr.ID)
+		assert.ElementsMatch(t, pr.Reviewers, restoredPR.Reviewers)
+		u, _ := repo.GetUser(ctx, "un_r1")
+		assert.True(t, u.IsActive)
+	})
+
+	t.Run("UndoOperation_SkipsMergedPR", func(t *testing.T) {
+		tName := "undo-merged"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "um_a", "A", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "um_r", "R", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreatePR(ctx, "pr-undo-merged", "T", "um_a", CreatePROptions{})
+		require.NoError(t, err)
+		deact, err := svc.DeactivateTeamAndRemoveReviews(ctx, tName, DeactivationOptions{})
+		require.NoError(t, err)
+		_, err = svc.MergePR(ctx, "pr-undo-merged")
+		require.NoError(t, err)
+
+		res, err := svc.UndoOperation(ctx, deact.OperationID)
+
+		require.NoError(t, err)
+		assert.Empty(t, res.RestoredReviews)
+		require.Len(t, res.SkippedReviews, 1)
+		assert.Equal(t, "um_r", res.SkippedReviews[0].UserID)
+	})
+
+	t.Run("UndoOperation_Twice", func(t *testing.T) {
+		tName := "undo-twice"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ut_a", "A", tName, true)
+		require.NoError(t, err)
+		deact, err := svc.DeactivateTeamAndRemoveReviews(ctx, tName, DeactivationOptions{})
+		require.NoError(t, err)
+		_, err = svc.UndoOperation(ctx, deact.OperationID)
+		require.NoError(t, err)
+
+		_, err = svc.UndoOperation(ctx, deact.OperationID)
+
+		require.ErrorIs(t, err, domain.ErrAlreadyUndone)
+	})
+
+	t.Run("UndoOperation_NotFound", func(t *testing.T) {
+		_, err := svc.UndoOperation(ctx, 100500)
+
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"reviewer/internal/domain"
+)
+
+// authorizeReviewerChange проверяет, что состав ревьюеров PR меняет его автор или руководитель команды PR
+func (s *Service) authorizeReviewerChange(ctx context.Context, pr domain.PullRequest, actorID string) error {
+	if actorID == pr.AuthorID {
+		return nil
+	}
+	team, err := s.repo.GetTeamByName(ctx, pr.TeamName)
+	if err != nil {
+		return fmt.Errorf("getting team: %w", err)
+	}
+	if team.LeadID != nil && *team.LeadID == actorID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// checkReviewerEligible проверяет, что пользователь мог бы быть выбран ревьюером PR автоматически:
+// он активен, не отсутствует, состоит в команде, отвечающей за PR, и не исключён для этого PR.
+// Лимит открытых ревью при ручном назначении не проверяется
+func (s *Service) checkReviewerEligible(ctx context.Context, pr domain.PullRequest, userID string) error {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("getting reviewer: %w", err)
+	}
+	available, err := s.repo.GetAvailableUsers(ctx, []string{userID}, s.now())
+	if err != nil {
+		return fmt.Errorf("checking availability: %w", err)
+	}
+	if len(available) == 0 {
+		return fmt.Errorf("%w: user is inactive or absent", domain.ErrNotEligible)
+	}
+	teams, err := s.poolTeams(ctx, pr)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(teams, user.TeamName) {
+		return fmt.Errorf("%w: user's team %q is not responsible for the pull request", domain.ErrNotEligible, user.TeamName)
+	}
+	reasons, err := s.exclusionReasons(ctx, pr)
+	if err != nil {
+		return err
+	}
+	if reason, ok := reasons[userID]; ok {
+		return fmt.Errorf("%w: %s", domain.ErrNotEligible, reason)
+	}
+	return nil
+}
+
+// AddReviewer вручную добавляет ревьюера на открытый PR. Менять состав могут только автор
+// и руководитель команды PR; действие записывается в историю назначений
+func (s *Service) AddReviewer(ctx context.Context, prID, userID, actorID string) (domain.PullRequest, error) {
+	var result domain.PullRequest
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		pr, err := s.repo.GetPRForUpdate(ctxTx, prID)
+		if err != nil {
+			return err
+		}
+		if pr.Status == domain.PRStatusMerged {
+			return domain.ErrPRMerged
+		}
+		if err := s.authorizeReviewerChange(ctxTx, pr, actorID); err != nil {
+			return err
+		}
+		if slices.Contains(pr.Reviewers, userID) {
+			return domain.ErrReviewerExist
+		}
+		if err := s.checkReviewerEligible(ctxTx, pr, userID); err != nil {
+			return err
+		}
+		if err := s.repo.AddManualReviewer(ctxTx, prID, userID, actorID); err != nil {
+			return err
+		}
+		if err := s.enqueueNotifications(ctxTx, assignedNotification(prID, userID)); err != nil {
+			return err
+		}
+		result, err = s.repo.GetPR(ctxTx, prID)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	return result, nil
+}
+
+// RemoveReviewer вручную снимает ревьюера с открытого PR без подбора замены.
+// Если ревьюеров станет меньше нужного, PR попадёт в список недоукомплектованных
+func (s *Service) RemoveReviewer(ctx context.Context, prID, userID, actorID string) (domain.PullRequest, error) {
+	var result domain.PullRequest
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		pr, err := s.repo.GetPRForUpdate(ctxTx, prID)
+		if err != nil {
+			return err
+		}
+		if pr.Status == domain.PRStatusMerged {
+			return domain.ErrPRMerged
+		}
+		if err := s.authorizeReviewerChange(ctxTx, pr, actorID); err != nil {
+			return err
+		}
+		if err := s.repo.RemoveReviewer(ctxTx, prID, userID); err != nil {
+			return err
+		}
+		if err := s.repo.RecordReviewerRemoval(ctxTx, prID, userID, actorID); err != nil {
+			return err
+		}
+		result, err = s.repo.GetPR(ctxTx, prID)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_ReviewerOverride(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	tName := "override-team"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	_, err = svc.CreateTeam(ctx, "override-other")
+	require.NoError(t, err)
+	for _, id := range []string{"ov_author", "ov_lead", "ov_r1", "ov_r2", "ov_extra"} {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+	}
+	_, err = svc.CreateUser(ctx, "ov_off", "ov_off", tName, false)
+	require.NoError(t, err)
+	_, err = svc.CreateUser(ctx, "ov_outsider", "ov_outsider", "override-other", true)
+	require.NoError(t, err)
+	lead := "ov_lead"
+	team, err := svc.SetTeamLead(ctx, tName, &lead)
+	require.NoError(t, err)
+	require.Equal(t, &lead, team.LeadID)
+	_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "ov-pr", Title: "T", AuthorID: "ov_author", TeamName: tName, Status: domain.PRStatusOpen})
+	require.NoError(t, err)
+	require.NoError(t, repo.AddReviewers(ctx, "ov-pr", []string{"ov_r1"}))
+
+	t.Run("SetTeamLead_NotMember", func(t *testing.T) {
+		outsider := "ov_outsider"
+
+		_, err := svc.SetTeamLead(ctx, tName, &outsider)
+
+		assert.ErrorIs(t, err, domain.ErrNotTeamMember)
+	})
+
+	t.Run("AddReviewer_ByLead", func(t *testing.T) {
+		pr, err := svc.AddReviewer(ctx, "ov-pr", "ov_extra", "ov_lead")
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"ov_r1", "ov_extra"}, pr.Reviewers)
+		history, err := svc.GetAssignmentHistory(ctx, "ov-pr")
+		require.NoError(t, err)
+		last := history[len(history)-1]
+		assert.Equal(t, "ov_extra", last.ReviewerID)
+		assert.Equal(t, domain.AssignmentActionAssigned, last.Action)
+		assert.Equal(t, domain.MatchReasonManual, last.Reason)
+		assert.Equal(t, "ov_lead", last.ActorID)
+	})
+
+	t.Run("AddReviewer_Rejected", func(t *testing.T) {
+		cases := map[string]struct {
+			userID, actorID string
+			want            error
+		}{
+			"Exists":     {"ov_r1", "ov_author", domain.ErrReviewerExist},
+			"Forbidden":  {"ov_r2", "ov_r1", domain.ErrForbidden},
+			"Inactive":   {"ov_off", "ov_author", domain.ErrNotEligible},
+			"OtherTeam":  {"ov_outsider", "ov_author", domain.ErrNotEligible},
+			"Author":     {"ov_author", "ov_lead", domain.ErrNotEligible},
+			"NoSuchUser": {"ov_ghost", "ov_author", domain.ErrNotFound},
+		}
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := svc.AddReviewer(ctx, "ov-pr", c.userID, c.actorID)
+
+				assert.ErrorIs(t, err, c.want)
+			})
+		}
+	})
+
+	t.Run("RemoveReviewer_ByAuthor", func(t *testing.T) {
+		pr, err := svc.RemoveReviewer(ctx, "ov-pr", "ov_r1", "ov_author")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ov_extra"}, pr.Reviewers)
+		history, err := svc.GetAssignmentHistory(ctx, "ov-pr")
+		require.NoError(t, err)
+		last := history[len(history)-1]
+		assert.Equal(t, "ov_r1", last.ReviewerID)
+		assert.Equal(t, domain.AssignmentActionRemoved, last.Action)
+		assert.Equal(t, "ov_author", last.ActorID)
+	})
+
+	t.Run("RemoveReviewer_NotAssigned", func(t *testing.T) {
+		_, err := svc.RemoveReviewer(ctx, "ov-pr", "ov_r2", "ov_author")
+
+		assert.ErrorIs(t, err, domain.ErrNotAssigned)
+	})
+
+	t.Run("Merged", func(t *testing.T) {
+		_, err := svc.MergePR(ctx, "ov-pr")
+		require.NoError(t, err)
+
+		_, err = svc.AddReviewer(ctx, "ov-pr", "ov_r2", "ov_author")
+		assert.ErrorIs(t, err, domain.ErrPRMerged)
+		_, err = svc.RemoveReviewer(ctx, "ov-pr", "ov_extra", "ov_author")
+		assert.ErrorIs(t, err, domain.ErrPRMerged)
+	})
+}
@@ -4,48 +4,77 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"reviewer/internal/domain"
-	"reviewer/internal/repository"
 )
 
-func (s *Service) CreatePR(ctx context.Context, prID, title, authorID string) (*domain.PullRequest, error) {
+type CreatePROptions struct {
+	Repository       string
+	Labels           []string
+	ChangedPaths     []string
+	ExcludeReviewers []string
+}
+
+type ReassignOptions struct {
+	ExcludeReviewers []string
+	// NewReviewerID задаёт замену явно вместо подбора; лимит открытых ревью для неё не проверяется
+	NewReviewerID string
+}
+
+// newPRModel собирает ещё не сохранённый PR: команда PR — команда автора или основная команда-владелец репозитория
+func (s *Service) newPRModel(ctx context.Context, prID, title, authorID string, opts CreatePROptions) (*domain.PullRequest, error) {
 	author, err := s.repo.GetUser(ctx, authorID)
 	if err != nil {
 		return nil, fmt.Errorf("getting author: %w", err)
 	}
 
-	candidates, err := s.repo.GetActiveTeamMembers(ctx, author.TeamName)
-	if err != nil {
-		return nil, fmt.Errorf("getting candidates: %w", err)
+	prModel := &domain.PullRequest{
+		ID:                prID,
+		Title:             title,
+		AuthorID:          author.ID,
+		TeamName:          author.TeamName,
+		Status:            domain.PRStatusOpen,
+		NeededReviewers:   domain.DefaultNeededReviewers,
+		Repository:        opts.Repository,
+		Labels:            normalizeTags(opts.Labels),
+		ChangedPaths:      normalizePaths(opts.ChangedPaths),
+		ExcludedReviewers: uniqueStrings(opts.ExcludeReviewers),
 	}
-
-	validCandidates := make([]domain.User, 0, len(candidates))
-	for _, u := range candidates {
-		if u.ID != author.ID {
-			validCandidates = append(validCandidates, u)
+	if opts.Repository != "" {
+		repo, err := s.repo.GetRepository(ctx, opts.Repository)
+		if err != nil {
+			return nil, fmt.Errorf("getting repository: %w", err)
+		}
+		if len(repo.OwnerTeams) > 0 {
+			prModel.TeamName = repo.OwnerTeams[0]
 		}
 	}
+	return prModel, nil
+}
 
-	selectedUsers := s.pickRandomReviewers(validCandidates, 2)
-	selectedIDs := make([]string, len(selectedUsers))
-	for i, u := range selectedUsers {
-		selectedIDs[i] = u.ID
+func (s *Service) CreatePR(ctx context.Context, prID, title, authorID string, opts CreatePROptions) (*domain.PullRequest, error) {
+	prModel, err := s.newPRModel(ctx, prID, title, authorID, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	var createdPR *domain.PullRequest
-	txRepo, ok := s.repo.(repository.Transactor)
-	if !ok {
-		return nil, errors.New("repository does not support transactions")
-	}
+	var sel reviewerSelection
+	err = s.runInTx(ctx, func(ctxTx context.Context) error {
+		candidates, err := s.candidatePool(ctxTx, *prModel)
+		if err != nil {
+			return err
+		}
+		excluded, err := s.reviewExclusions(ctxTx, *prModel)
+		if err != nil {
+			return err
+		}
 
-	err = txRepo.RunInTx(ctx, func(ctxTx context.Context) error {
-		prModel := &domain.PullRequest{
-			ID:       prID,
-			Title:    title,
-			AuthorID: author.ID,
-			TeamName: author.TeamName,
-			Status:   domain.PRStatusOpen,
+		sel, err = s.selectReviewers(ctxTx, *prModel, candidates, nil, excluded, nil, prModel.NeededReviewers)
+		if err != nil {
+			return err
 		}
 
 		pr, err := s.repo.CreatePR(ctxTx, prModel)
@@ -53,9 +82,19 @@ func (s *Service) CreatePR(ctx context.Context, prID, title, authorID string) (*
 			return err
 		}
 
-		if err := s.repo.AddReviewers(ctxTx, pr.ID, selectedIDs); err != nil {
+		if err := s.saveSelection(ctxTx, pr.ID, sel); err != nil {
+			return err
+		}
+		for _, m := range sel.matches {
+			excluded[m.UserID] = true
+		}
+		violation, err := s.assignShadowReviewer(ctxTx, sel.rng, *pr, candidates, excluded)
+		if err != nil {
 			return err
 		}
+		if violation != nil {
+			sel.violations = append(sel.violations, *violation)
+		}
 
 		tempPR, err := s.repo.GetPR(ctxTx, pr.ID)
 		if err != nil {
@@ -69,9 +108,202 @@ func (s *Service) CreatePR(ctx context.Context, prID, title, authorID string) (*
 		return nil, err
 	}
 
+	createdPR.CapacityExhausted = sel.capacityExhausted
+	createdPR.Violations = sel.violations
+	createdPR.Matches = sel.matches
+	createdPR.RuleTrace = sel.trace
 	return createdPR, nil
 }
 
+// PreviewPR подбирает ревьюеров для PR так же, как CreatePR, но ничего не сохраняет и не блокирует:
+// возвращает пул кандидатов, исключённых с причинами, пример выбора и вероятности выбора
+func (s *Service) PreviewPR(ctx context.Context, authorID string, opts CreatePROptions) (*domain.AssignmentPreview, error) {
+	prModel, err := s.newPRModel(ctx, "", "", authorID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &domain.AssignmentPreview{
+		TeamName:        prModel.TeamName,
+		NeededReviewers: prModel.NeededReviewers,
+		Candidates:      []domain.ReviewerLoad{},
+		Exclusions:      []domain.CandidateExclusion{},
+	}
+	err = s.runInTx(ctx, func(ctxTx context.Context) error {
+		team, err := s.repo.GetTeamByName(ctxTx, prModel.TeamName)
+		if err != nil {
+			return fmt.Errorf("getting team: %w", err)
+		}
+		teams, err := s.poolTeams(ctxTx, *prModel)
+		if err != nil {
+			return err
+		}
+		candidates, err := s.candidatePool(ctxTx, *prModel)
+		if err != nil {
+			return err
+		}
+		reasons, err := s.exclusionReasons(ctxTx, *prModel)
+		if err != nil {
+			return err
+		}
+		excluded := make(map[string]bool, len(reasons))
+		for id := range reasons {
+			excluded[id] = true
+		}
+
+		owners, err := s.prOwners(ctxTx, *prModel, excluded, prModel.NeededReviewers)
+		if err != nil {
+			return err
+		}
+		ids := ownerIDs(owners)
+		for _, u := range candidates {
+			ids = append(ids, u.ID)
+		}
+		loads, err := s.readReviewerLoads(ctxTx, ids, false)
+		if err != nil {
+			return err
+		}
+
+		sel, err := s.chooseReviewers(ctxTx, *prModel, candidates, owners, loads, excluded, nil, prModel.NeededReviewers)
+		if err != nil {
+			return err
+		}
+		preview.Reviewers = append([]domain.ReviewerMatch{}, sel.matches...)
+		preview.CapacityExhausted = sel.capacityExhausted
+		preview.Violations = sel.violations
+		preview.RuleTrace = sel.trace
+
+		byID := slices.SortedFunc(slices.Values(candidates), func(a, b domain.User) int { return strings.Compare(a.ID, b.ID) })
+		for _, u := range byID {
+			l, ok := loads[u.ID]
+			if !ok {
+				continue
+			}
+			preview.Candidates = append(preview.Candidates, l)
+			if _, ok := reasons[l.UserID]; !ok && !l.HasCapacity() {
+				reasons[l.UserID] = domain.ExclusionAtCapacity
+				excluded[l.UserID] = true
+			}
+		}
+		for _, u := range candidates {
+			if reason, ok := reasons[u.ID]; ok {
+				preview.Exclusions = append(preview.Exclusions, domain.CandidateExclusion{UserID: u.ID, Reason: reason})
+			}
+		}
+		if err := s.addUnavailableExclusions(ctxTx, preview, teams, candidates); err != nil {
+			return err
+		}
+
+		preview.Strategy = team.AssignmentStrategy
+		if sel.trace != nil {
+			preview.Strategy = domain.AssignmentStrategyRuleSet
+			return nil
+		}
+		penalties, err := s.pairPenalties(ctxTx, team, *prModel)
+		if err != nil {
+			return err
+		}
+		_, seniorRequired, err := s.seniorRequirement(ctxTx, team, *prModel)
+		if err != nil {
+			return err
+		}
+		preview.Probabilities = assignmentProbabilities(prModel.ChangedPaths, owners, loads, candidates, excluded,
+			seniorRequired, prModel.Labels, penalties, prModel.NeededReviewers)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// addUnavailableExclusions добавляет в предпросмотр участников команд teams, не попавших в пул
+func (s *Service) addUnavailableExclusions(ctx context.Context, preview *domain.AssignmentPreview, teams []string, pool []domain.User) error {
+	for _, name := range teams {
+		team, err := s.repo.GetTeamByName(ctx, name)
+		if err != nil {
+			return fmt.Errorf("getting team: %w", err)
+		}
+		for _, m := range team.Members {
+			if !slices.ContainsFunc(pool, func(u domain.User) bool { return u.ID == m.UserID }) {
+				preview.Exclusions = append(preview.Exclusions, domain.CandidateExclusion{UserID: m.UserID, Reason: domain.ExclusionUnavailable})
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) GetPR(ctx context.Context, prID string) (domain.PullRequest, error) {
+	pr, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	pr.Matches, err = s.repo.GetReviewerMatches(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, fmt.Errorf("getting reviewer matches: %w", err)
+	}
+	trace, err := s.repo.GetRuleTrace(ctx, prID)
+	switch {
+	case err == nil:
+		pr.RuleTrace = &trace
+	case !errors.Is(err, domain.ErrNotFound):
+		return domain.PullRequest{}, fmt.Errorf("getting rule trace: %w", err)
+	}
+	return pr, nil
+}
+
+// reassignTo заменяет ревьюера PR на явно выбранного пользователя, если тот мог бы ревьюить PR
+// и ещё не назначен. Должна вызываться внутри транзакции, заблокировавшей PR
+func (s *Service) reassignTo(ctx context.Context, pr domain.PullRequest, oldReviewerID, newReviewerID string) (domain.User, domain.PullRequest, error) {
+	if slices.Contains(pr.Reviewers, newReviewerID) {
+		return domain.User{}, domain.PullRequest{}, domain.ErrReviewerExist
+	}
+	if err := s.checkReviewerEligible(ctx, pr, newReviewerID); err != nil {
+		return domain.User{}, domain.PullRequest{}, err
+	}
+	newReviewer, err := s.repo.GetUser(ctx, newReviewerID)
+	if err != nil {
+		return domain.User{}, domain.PullRequest{}, err
+	}
+	if err := s.repo.RemoveReviewer(ctx, pr.ID, oldReviewerID); err != nil {
+		return domain.User{}, domain.PullRequest{}, err
+	}
+	if err := s.repo.AddManualReviewer(ctx, pr.ID, newReviewerID, ""); err != nil {
+		return domain.User{}, domain.PullRequest{}, err
+	}
+	if err := s.enqueueNotifications(ctx, assignedNotification(pr.ID, newReviewerID)); err != nil {
+		return domain.User{}, domain.PullRequest{}, err
+	}
+	result, err := s.repo.GetPR(ctx, pr.ID)
+	if err != nil {
+		return domain.User{}, domain.PullRequest{}, err
+	}
+	return newReviewer, result, nil
+}
+
+// GetAssignmentHistory возвращает все назначения ревьюеров на PR, включая снятых впоследствии
+func (s *Service) GetAssignmentHistory(ctx context.Context, prID string) ([]domain.AssignmentRecord, error) {
+	if _, err := s.repo.GetPR(ctx, prID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAssignmentHistory(ctx, prID)
+}
+
+// SubmitReview записывает реакцию назначенного ревьюера на открытый PR
+func (s *Service) SubmitReview(ctx context.Context, prID, userID string, decision domain.ReviewDecision) (domain.Review, error) {
+	pr, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if pr.Status == domain.PRStatusMerged {
+		return domain.Review{}, domain.ErrPRMerged
+	}
+	if !slices.Contains(pr.Reviewers, userID) {
+		return domain.Review{}, domain.ErrNotAssigned
+	}
+	return s.repo.AddReview(ctx, domain.Review{PullRequestID: prID, ReviewerID: userID, Decision: decision})
+}
+
 func (s *Service) MergePR(ctx context.Context, prID string) (domain.PullRequest, error) {
 	pr, err := s.repo.GetPR(ctx, prID)
 	if err != nil {
@@ -93,73 +325,171 @@ func (s *Service) MergePR(ctx context.Context, prID string) (domain.PullRequest,
 	return pr, nil
 }
 
-func (s *Service) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (domain.PullRequest, domain.User, error) {
+func (s *Service) ReassignReviewer(ctx context.Context, prID, oldReviewerID string, opts ReassignOptions) (domain.PullRequest, domain.User, error) {
 	var resultPR domain.PullRequest
 	var newReviewer domain.User
 
-	txRepo, ok := s.repo.(repository.Transactor)
-	if !ok {
-		return domain.PullRequest{}, domain.User{}, errors.New("repository does not support transactions")
-	}
-
-	err := txRepo.RunInTx(ctx, func(ctxTx context.Context) error {
-		pr, err := s.repo.GetPRForUpdate(ctxTx, prID)
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		var err error
+		resultPR, newReviewer, err = s.reassignReviewer(ctxTx, prID, oldReviewerID, opts)
 		if err != nil {
 			return err
 		}
+		return s.enqueueNotifications(ctxTx, reassignedNotification(prID, oldReviewerID, newReviewer.ID))
+	})
 
-		if pr.Status == domain.PRStatusMerged {
-			return domain.ErrPRMerged
-		}
+	return resultPR, newReviewer, err
+}
 
-		isAssigned := false
-		currentReviewerIDs := make(map[string]bool)
-		for _, rID := range pr.Reviewers {
-			currentReviewerIDs[rID] = true
-			if rID == oldReviewerID {
-				isAssigned = true
-			}
-		}
-		if !isAssigned {
-			return domain.ErrNotAssigned
-		}
+// reassignFailures — ошибки reassignReviewer, из-за которых не переназначается только одно ревью;
+// они возникают до изменений в PR, поэтому вызывающая транзакция может продолжаться
+var reassignFailures = []error{domain.ErrNoCandidates, domain.ErrCapacityFull, domain.ErrReviewerExist, domain.ErrNotEligible}
 
-		candidates, err := s.repo.GetActiveTeamMembers(ctxTx, pr.TeamName)
-		if err != nil {
-			return err
+func isReassignFailure(err error) bool {
+	for _, target := range reassignFailures {
+		if errors.Is(err, target) {
+			return true
 		}
+	}
+	return false
+}
 
-		possibleReplacements := make([]domain.User, 0)
-		for _, c := range candidates {
-			if c.ID == pr.AuthorID {
-				continue
-			}
-			if c.ID == oldReviewerID {
-				continue
-			}
-			if _, exists := currentReviewerIDs[c.ID]; exists {
-				continue
-			}
-			possibleReplacements = append(possibleReplacements, c)
+// reassignReviewer заменяет ревьюера PR; доменные ошибки возвращаются до любых изменений,
+// кроме сохранения исключений из opts. Должна вызываться внутри транзакции
+func (s *Service) reassignReviewer(ctx context.Context, prID, oldReviewerID string, opts ReassignOptions) (domain.PullRequest, domain.User, error) {
+	pr, err := s.repo.GetPRForUpdate(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, domain.User{}, err
+	}
+
+	if pr.Status == domain.PRStatusMerged {
+		return domain.PullRequest{}, domain.User{}, domain.ErrPRMerged
+	}
+
+	isAssigned := false
+	currentReviewerIDs := make(map[string]bool)
+	for _, rID := range pr.Reviewers {
+		currentReviewerIDs[rID] = true
+		if rID == oldReviewerID {
+			isAssigned = true
 		}
+	}
+	if !isAssigned {
+		return domain.PullRequest{}, domain.User{}, domain.ErrNotAssigned
+	}
 
-		if len(possibleReplacements) == 0 {
-			return domain.ErrNoCandidates
+	if exclude := uniqueStrings(opts.ExcludeReviewers); len(exclude) > 0 {
+		if err := s.repo.AddPRExclusions(ctx, prID, exclude); err != nil {
+			return domain.PullRequest{}, domain.User{}, err
 		}
+		pr.ExcludedReviewers = append(pr.ExcludedReviewers, exclude...)
+	}
 
-		selected := s.pickRandomReviewers(possibleReplacements, 1)
-		newReviewer = selected[0]
+	if opts.NewReviewerID != "" {
+		newReviewer, resultPR, err := s.reassignTo(ctx, pr, oldReviewerID, opts.NewReviewerID)
+		return resultPR, newReviewer, err
+	}
 
-		if err := s.repo.RemoveReviewer(ctxTx, prID, oldReviewerID); err != nil {
-			return err
+	candidates, err := s.candidatePool(ctx, pr)
+	if err != nil {
+		return domain.PullRequest{}, domain.User{}, err
+	}
+
+	excluded, err := s.reviewExclusions(ctx, pr)
+	if err != nil {
+		return domain.PullRequest{}, domain.User{}, err
+	}
+	excluded[oldReviewerID] = true
+	kept := make([]string, 0, len(pr.Reviewers))
+	for id := range currentReviewerIDs {
+		excluded[id] = true
+		if id != oldReviewerID {
+			kept = append(kept, id)
 		}
-		if err := s.repo.AddReviewers(ctxTx, prID, []string{newReviewer.ID}); err != nil {
-			return err
+	}
+	sel, err := s.selectReviewers(ctx, pr, candidates, nil, excluded, kept, 1)
+	if err != nil {
+		return domain.PullRequest{}, domain.User{}, err
+	}
+	if len(sel.matches) == 0 {
+		if sel.capacityExhausted {
+			return domain.PullRequest{}, domain.User{}, domain.ErrCapacityFull
+		}
+		return domain.PullRequest{}, domain.User{}, domain.ErrNoCandidates
+	}
+	newReviewer, err := s.repo.GetUser(ctx, sel.matches[0].UserID)
+	if err != nil {
+		return domain.PullRequest{}, domain.User{}, err
+	}
+
+	if err := s.repo.RemoveReviewer(ctx, prID, oldReviewerID); err != nil {
+		return domain.PullRequest{}, domain.User{}, err
+	}
+	if err := s.saveSelection(ctx, prID, sel); err != nil {
+		return domain.PullRequest{}, domain.User{}, err
+	}
+
+	resultPR, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, domain.User{}, err
+	}
+	resultPR.Violations = sel.violations
+	resultPR.RuleTrace = sel.trace
+	return resultPR, newReviewer, nil
+}
+
+func (s *Service) ListUnderstaffedPRs(ctx context.Context, teamName string) ([]domain.UnderstaffedPR, error) {
+	return s.repo.ListUnderstaffedPRs(ctx, teamName)
+}
+
+func (s *Service) BackfillUnderstaffedPRs(ctx context.Context, teamName string) ([]domain.PRBackfill, error) {
+	if teamName != "" {
+		if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+			return nil, err
 		}
+	}
 
-		resultPR, err = s.repo.GetPR(ctxTx, prID)
+	var results []domain.PRBackfill
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		var err error
+		results, err = s.backfillTeam(ctxTx, teamName)
 		return err
 	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
 
-	return resultPR, newReviewer, err
+// backfillTeam добирает ревьюеров на открытые PR команды, у которых их меньше требуемого.
+// Пустое имя команды означает все команды
+func (s *Service) backfillTeam(ctx context.Context, teamName string) ([]domain.PRBackfill, error) {
+	understaffed, err := s.repo.ListUnderstaffedPRs(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("listing understaffed PRs: %w", err)
+	}
+
+	results := make([]domain.PRBackfill, 0, len(understaffed))
+	for _, u := range understaffed {
+		pr, err := s.repo.GetPRForUpdate(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		missing := pr.NeededReviewers - len(pr.Reviewers)
+		if pr.Status != domain.PRStatusOpen || missing <= 0 {
+			continue
+		}
+		sel, err := s.fillReviewers(ctx, pr, missing, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, domain.PRBackfill{
+			PullRequestID:     pr.ID,
+			Added:             sel.userIDs(),
+			Understaffed:      len(sel.matches) < missing,
+			CapacityExhausted: sel.capacityExhausted,
+			Violations:        sel.violations,
+		})
+	}
+	return results, nil
 }
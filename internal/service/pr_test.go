@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,7 +42,7 @@ func TestService_PR(t *testing.T) {
 		_, err = svc.CreateUser(ctx, "s_r3", "Rev3", tName, true)
 		require.NoError(t, err)
 
-		pr, err := svc.CreatePR(ctx, "pr-1", "Test", "s_auth")
+		pr, err := svc.CreatePR(ctx, "pr-1", "Test", "s_auth", CreatePROptions{})
 
 		require.NoError(t, err)
 		assert.Len(t, pr.Reviewers, 2)
@@ -55,7 +58,7 @@ func TestService_PR(t *testing.T) {
 		_, err = svc.CreateUser(ctx, "sm_r", "Rev1", tName, true)
 		require.NoError(t, err)
 
-		pr, err := svc.CreatePR(ctx, "pr-small", "Test", "sm_a")
+		pr, err := svc.CreatePR(ctx, "pr-small", "Test", "sm_a", CreatePROptions{})
 
 		require.NoError(t, err)
 		require.Len(t, pr.Reviewers, 1)
@@ -68,7 +71,7 @@ func TestService_PR(t *testing.T) {
 		require.NoError(t, err)
 		_, err = svc.CreateUser(ctx, "m_u1", "M1", tName, true)
 		require.NoError(t, err)
-		pr, _ := svc.CreatePR(ctx, "pr-merge", "M", "m_u1")
+		pr, _ := svc.CreatePR(ctx, "pr-merge", "M", "m_u1", CreatePROptions{})
 
 		merged1, err := svc.MergePR(ctx, pr.ID)
 		require.NoError(t, err)
@@ -102,7 +105,7 @@ func TestService_PR(t *testing.T) {
 		err = repo.AddReviewers(ctx, "pr-re", []string{"re_old"})
 		require.NoError(t, err)
 
-		updatedPR, newReviewer, err := svc.ReassignReviewer(ctx, "pr-re", "re_old")
+		updatedPR, newReviewer, err := svc.ReassignReviewer(ctx, "pr-re", "re_old", ReassignOptions{})
 
 		require.NoError(t, err)
 		assert.Equal(t, "re_new", newReviewer.ID)
@@ -110,6 +113,38 @@ func TestService_PR(t *testing.T) {
 		assert.NotContains(t, updatedPR.Reviewers, "re_old", "Old reviewer should be gone")
 	})
 
+	t.Run("ReassignReviewer_ExplicitTarget", func(t *testing.T) {
+		tName := "reassign-explicit"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"rx_a", "rx_old", "rx_keep", "rx_want", "rx_other"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+		zero := 0
+		_, err = svc.SetUserCapacity(ctx, "rx_want", &zero)
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "pr-rx", Title: "T", AuthorID: "rx_a", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		require.NoError(t, repo.AddReviewers(ctx, "pr-rx", []string{"rx_old", "rx_keep"}))
+
+		_, _, err = svc.ReassignReviewer(ctx, "pr-rx", "rx_old", ReassignOptions{NewReviewerID: "rx_keep"})
+		assert.ErrorIs(t, err, domain.ErrReviewerExist)
+		_, _, err = svc.ReassignReviewer(ctx, "pr-rx", "rx_old", ReassignOptions{NewReviewerID: "rx_a"})
+		assert.ErrorIs(t, err, domain.ErrNotEligible)
+		_, _, err = svc.ReassignReviewer(ctx, "pr-rx", "rx_old", ReassignOptions{NewReviewerID: "rx_other", ExcludeReviewers: []string{"rx_other"}})
+		assert.ErrorIs(t, err, domain.ErrNotEligible)
+
+		updatedPR, newReviewer, err := svc.ReassignReviewer(ctx, "pr-rx", "rx_old", ReassignOptions{NewReviewerID: "rx_want"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "rx_want", newReviewer.ID)
+		assert.ElementsMatch(t, []string{"rx_keep", "rx_want"}, updatedPR.Reviewers)
+		matches, err := repo.GetReviewerMatches(ctx, "pr-rx")
+		require.NoError(t, err)
+		assert.Contains(t, matches, domain.ReviewerMatch{UserID: "rx_want", Reason: domain.MatchReasonManual, MatchedTags: []string{}})
+	})
+
 	t.Run("ReassignReviewer_NoCandidates", func(t *testing.T) {
 		tName := "no-cand-team"
 		_, err := svc.CreateTeam(ctx, tName)
@@ -118,11 +153,313 @@ func TestService_PR(t *testing.T) {
 		require.NoError(t, err)
 		_, err = svc.CreateUser(ctx, "nc_r", "Rev", tName, true)
 		require.NoError(t, err)
-		pr, err := svc.CreatePR(ctx, "pr-nc", "Test", "nc_a")
+		pr, err := svc.CreatePR(ctx, "pr-nc", "Test", "nc_a", CreatePROptions{})
 		require.NoError(t, err)
 
-		_, _, err = svc.ReassignReviewer(ctx, pr.ID, "nc_r")
+		_, _, err = svc.ReassignReviewer(ctx, pr.ID, "nc_r", ReassignOptions{})
 
 		require.ErrorIs(t, err, domain.ErrNoCandidates)
 	})
+	t.Run("Backfill_OnUserActivation", func(t *testing.T) {
+		tName := "backfill-activate"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ba_a", "Author", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ba_r1", "Rev1", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ba_r2", "Rev2", tName, false)
+		require.NoError(t, err)
+		pr, err := svc.CreatePR(ctx, "pr-backfill", "T", "ba_a", CreatePROptions{})
+		require.NoError(t, err)
+		require.Len(t, pr.Reviewers, 1)
+		isActive := true
+
+		_, err = svc.UpdateUser(ctx, "ba_r2", &isActive)
+
+		require.NoError(t, err)
+		updated, _ := repo.GetPR(ctx, "pr-backfill")
+		assert.ElementsMatch(t, []string{"ba_r1", "ba_r2"}, updated.Reviewers)
+	})
+
+	t.Run("Backfill_OnJoiningTeam", func(t *testing.T) {
+		tName := "backfill-join"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "bj_a", "Author", tName, true)
+		require.NoError(t, err)
+		pr, err := svc.CreatePR(ctx, "pr-backfill-join", "T", "bj_a", CreatePROptions{})
+		require.NoError(t, err)
+		require.Empty(t, pr.Reviewers)
+
+		_, err = svc.CreateUser(ctx, "bj_r", "Rev", tName, true)
+
+		require.NoError(t, err)
+		updated, _ := repo.GetPR(ctx, "pr-backfill-join")
+		assert.Equal(t, []string{"bj_r"}, updated.Reviewers)
+		understaffed, err := svc.ListUnderstaffedPRs(ctx, tName)
+		require.NoError(t, err)
+		require.Len(t, understaffed, 1)
+		assert.Equal(t, "pr-backfill-join", understaffed[0].ID)
+	})
+
+	t.Run("BackfillUnderstaffedPRs_Manual", func(t *testing.T) {
+		tName := "backfill-manual"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "bm_a", Username: "A", TeamName: tName, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "bm_r1", Username: "R1", TeamName: tName, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreateUser(ctx, domain.User{ID: "bm_r2", Username: "R2", TeamName: tName, IsActive: true})
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "pr-bm", Title: "T", AuthorID: "bm_a", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+
+		results, err := svc.BackfillUnderstaffedPRs(ctx, tName)
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.ElementsMatch(t, []string{"bm_r1", "bm_r2"}, results[0].Added)
+		assert.False(t, results[0].Understaffed)
+	})
+
+	t.Run("BackfillUnderstaffedPRs_UnknownTeam", func(t *testing.T) {
+		_, err := svc.BackfillUnderstaffedPRs(ctx, "unknown-team")
+
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("CreatePR_CapacityExhausted", func(t *testing.T) {
+		tName := "capacity-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "cs_a", "Author", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "cs_r1", "Rev1", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "cs_r2", "Rev2", tName, true)
+		require.NoError(t, err)
+		zero := 0
+		_, err = svc.SetUserCapacity(ctx, "cs_r2", &zero)
+		require.NoError(t, err)
+
+		pr, err := svc.CreatePR(ctx, "pr-cap-svc", "T", "cs_a", CreatePROptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cs_r1"}, pr.Reviewers)
+		assert.True(t, pr.CapacityExhausted)
+	})
+
+	t.Run("CreatePR_ConcurrentLastSlot", func(t *testing.T) {
+		tName := "capacity-race"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"cc_a1", "cc_a2", "cc_r"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+		zero, one := 0, 1
+		for _, id := range []string{"cc_a1", "cc_a2"} {
+			_, err = svc.SetUserCapacity(ctx, id, &zero)
+			require.NoError(t, err)
+		}
+		_, err = svc.SetUserCapacity(ctx, "cc_r", &one)
+		require.NoError(t, err)
+
+		for round := range 5 {
+			ids := []string{fmt.Sprintf("pr-race-%d-1", round), fmt.Sprintf("pr-race-%d-2", round)}
+			prs := make([]*domain.PullRequest, len(ids))
+			errs := make([]error, len(ids))
+			var wg sync.WaitGroup
+			for i, author := range []string{"cc_a1", "cc_a2"} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					prs[i], errs[i] = svc.CreatePR(ctx, ids[i], "T", author, CreatePROptions{})
+				}()
+			}
+			wg.Wait()
+
+			assigned := 0
+			for i := range ids {
+				require.NoError(t, errs[i])
+				assigned += len(prs[i].Reviewers)
+			}
+			assert.Equal(t, 1, assigned, "round %d", round)
+			for _, id := range ids {
+				_, err := svc.MergePR(ctx, id)
+				require.NoError(t, err)
+			}
+		}
+	})
+
+	t.Run("ReassignReviewer_CapacityFull", func(t *testing.T) {
+		tName := "capacity-reassign"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "cr_a", "Author", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "cr_r1", "Rev1", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "cr_r2", "Rev2", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreatePR(ctx, "pr-cap-reassign", "T", "cr_a", CreatePROptions{})
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "cr_r3", "Rev3", tName, false)
+		require.NoError(t, err)
+		zero, active := 0, true
+		_, err = svc.SetUserCapacity(ctx, "cr_r3", &zero)
+		require.NoError(t, err)
+		_, err = svc.UpdateUser(ctx, "cr_r3", &active)
+		require.NoError(t, err)
+
+		_, _, err = svc.ReassignReviewer(ctx, "pr-cap-reassign", "cr_r1", ReassignOptions{})
+
+		assert.ErrorIs(t, err, domain.ErrCapacityFull)
+	})
+
+	t.Run("CreatePR_PrefersExpertise", func(t *testing.T) {
+		tName := "expertise-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ex_a", "Author", tName, true)
+		require.NoError(t, err)
+		for _, id := range []string{"ex_r1", "ex_r2", "ex_r3", "ex_db"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+		_, err = svc.SetUserExpertise(ctx, "ex_db", []string{" DB ", "security", "db"})
+		require.NoError(t, err)
+
+		pr, err := svc.CreatePR(ctx, "pr-expertise", "T", "ex_a", CreatePROptions{Labels: []string{"db", "Frontend"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"db", "frontend"}, pr.Labels)
+		assert.Contains(t, pr.Reviewers, "ex_db")
+		require.Len(t, pr.Matches, 2)
+		for _, m := range pr.Matches {
+			if m.UserID == "ex_db" {
+				assert.Equal(t, domain.MatchReasonExpertise, m.Reason)
+				assert.Equal(t, []string{"db"}, m.MatchedTags)
+			} else {
+				assert.Equal(t, domain.MatchReasonRandom, m.Reason)
+			}
+		}
+	})
+
+	t.Run("PreviewPR", func(t *testing.T) {
+		tName := "preview-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"pv_a", "pv_r1", "pv_full", "pv_db", "pv_skip"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+		_, err = svc.CreateUser(ctx, "pv_off", "pv_off", tName, false)
+		require.NoError(t, err)
+		zero := 0
+		_, err = svc.SetUserCapacity(ctx, "pv_full", &zero)
+		require.NoError(t, err)
+		_, err = svc.SetUserExpertise(ctx, "pv_db", []string{"db"})
+		require.NoError(t, err)
+
+		preview, err := svc.PreviewPR(ctx, "pv_a", CreatePROptions{Labels: []string{"db"}, ExcludeReviewers: []string{"pv_skip"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.AssignmentStrategyRandom, preview.Strategy)
+		assert.Len(t, preview.Candidates, 5)
+		assert.ElementsMatch(t, []domain.CandidateExclusion{
+			{UserID: "pv_a", Reason: domain.ExclusionAuthor},
+			{UserID: "pv_full", Reason: domain.ExclusionAtCapacity},
+			{UserID: "pv_skip", Reason: domain.ExclusionRequested},
+			{UserID: "pv_off", Reason: domain.ExclusionUnavailable},
+		}, preview.Exclusions)
+		require.Len(t, preview.Reviewers, 2)
+		assert.ElementsMatch(t, []string{"pv_db", "pv_r1"}, reviewerSelection{matches: preview.Reviewers}.userIDs())
+		assert.Equal(t, map[string]float64{"pv_db": 1, "pv_r1": 1}, preview.Probabilities)
+		reviews, err := svc.ListPRsByReviewer(ctx, "pv_db")
+		require.NoError(t, err)
+		assert.Empty(t, reviews)
+	})
+
+	t.Run("PreviewPR_DoesNotLockCandidates", func(t *testing.T) {
+		tName := "preview-lock-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"pvl_a", "pvl_r1", "pvl_r2"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+
+		err = svc.runInTx(ctx, func(ctxTx context.Context) error {
+			if _, err := repo.LockReviewerLoads(ctxTx, []string{"pvl_r1", "pvl_r2"}); err != nil {
+				return err
+			}
+			previewCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			preview, err := svc.PreviewPR(previewCtx, "pvl_a", CreatePROptions{})
+			if err != nil {
+				return err
+			}
+			assert.Len(t, preview.Reviewers, 2)
+			assert.Len(t, preview.Candidates, 3)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("PreviewPR_SeniorSlotProbabilities", func(t *testing.T) {
+		tName := "preview-senior-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		seniority := map[string]domain.Seniority{
+			"pss_a":  domain.SeniorityMid,
+			"pss_s1": domain.SenioritySenior,
+			"pss_s2": domain.SenioritySenior,
+			"pss_m1": domain.SeniorityMid,
+			"pss_m2": domain.SeniorityMid,
+		}
+		for id, level := range seniority {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+			_, err = svc.SetUserSeniority(ctx, id, level)
+			require.NoError(t, err)
+		}
+		_, err = svc.SetTeamReviewRules(ctx, tName, domain.TeamReviewRules{RequireSenior: true})
+		require.NoError(t, err)
+
+		for range 3 {
+			preview, err := svc.PreviewPR(ctx, "pss_a", CreatePROptions{})
+
+			require.NoError(t, err)
+			require.Len(t, preview.Probabilities, 4)
+			assert.InDelta(t, 2.0/3, preview.Probabilities["pss_s1"], 1e-9)
+			assert.InDelta(t, 2.0/3, preview.Probabilities["pss_s2"], 1e-9)
+			assert.InDelta(t, 1.0/3, preview.Probabilities["pss_m1"], 1e-9)
+			assert.InDelta(t, 1.0/3, preview.Probabilities["pss_m2"], 1e-9)
+		}
+	})
+
+	t.Run("PreviewPR_SharedSlots", func(t *testing.T) {
+		tName := "preview-shared-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"pvs_a", "pvs_1", "pvs_2", "pvs_3", "pvs_4"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+
+		preview, err := svc.PreviewPR(ctx, "pvs_a", CreatePROptions{})
+
+		require.NoError(t, err)
+		assert.Len(t, preview.Reviewers, 2)
+		assert.Equal(t, map[string]float64{"pvs_1": 0.5, "pvs_2": 0.5, "pvs_3": 0.5, "pvs_4": 0.5}, preview.Probabilities)
+	})
+
+	t.Run("PreviewPR_UnknownAuthor", func(t *testing.T) {
+		_, err := svc.PreviewPR(ctx, "nobody", CreatePROptions{})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
 }
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"reviewer/internal/domain"
+)
+
+// DefaultRebalanceTolerance — допустимая разница нагрузки участников, если она не задана в запросе
+const DefaultRebalanceTolerance = 1
+
+// RebalanceTeam переносит открытые ревью от наиболее загруженных активных участников команды
+// к наименее загруженным, пока разница нагрузки не станет не больше tolerance или переносить
+// станет нечего. Новый ревьюер проходит те же проверки, что и при явном переназначении:
+// он не автор, не исключён для PR и не упёрся в лимит. При dryRun переносы только рассчитываются
+func (s *Service) RebalanceTeam(ctx context.Context, teamName string, tolerance int, dryRun bool) (*domain.RebalanceResult, error) {
+	if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+	// при нулевой разнице перенос лишь поменял бы участников местами
+	tolerance = max(tolerance, 1)
+
+	result := &domain.RebalanceResult{
+		TeamName:  teamName,
+		DryRun:    dryRun,
+		Tolerance: tolerance,
+		Moves:     []domain.RebalanceMove{},
+		Loads:     []domain.ReviewerLoad{},
+	}
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		members, err := s.repo.GetActiveTeamMembers(ctxTx, teamName, s.now())
+		if err != nil {
+			return fmt.Errorf("getting team members: %w", err)
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		ids := make([]string, len(members))
+		for i, u := range members {
+			ids[i] = u.ID
+		}
+		// PR блокируются раньше нагрузки участников и по возрастанию id — в том же порядке,
+		// что и при создании PR и переназначении, иначе параллельные запросы ловят deadlock.
+		// Пробный прогон ничего не меняет и потому ничего не блокирует
+		assignments, err := s.repo.ListOpenReviewAssignments(ctxTx, ids)
+		if err != nil {
+			return fmt.Errorf("listing open reviews: %w", err)
+		}
+		prIDs := make([]string, len(assignments))
+		for i, a := range assignments {
+			prIDs[i] = a.PullRequestID
+		}
+		slices.Sort(prIDs)
+		prIDs = slices.Compact(prIDs)
+
+		b := &rebalancer{
+			svc:       s,
+			reviews:   make(map[string][]string),
+			prs:       make(map[string]domain.PullRequest),
+			tolerance: tolerance,
+		}
+		isMember := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			isMember[id] = true
+		}
+		for _, prID := range prIDs {
+			var pr domain.PullRequest
+			if dryRun {
+				pr, err = s.repo.GetPR(ctxTx, prID)
+			} else {
+				pr, err = s.repo.GetPRForUpdate(ctxTx, prID)
+			}
+			if err != nil {
+				return err
+			}
+			// PR мог быть слит до того, как его удалось заблокировать
+			if pr.Status != domain.PRStatusOpen {
+				continue
+			}
+			b.prs[prID] = pr
+			for _, rID := range pr.Reviewers {
+				if isMember[rID] {
+					b.reviews[rID] = append(b.reviews[rID], prID)
+				}
+			}
+		}
+
+		if dryRun {
+			b.loads, err = s.repo.GetReviewerLoads(ctxTx, ids)
+		} else {
+			b.loads, err = s.repo.LockReviewerLoads(ctxTx, ids)
+		}
+		if err != nil {
+			return fmt.Errorf("reading reviewer loads: %w", err)
+		}
+		result.SpreadBefore = loadSpread(b.loads)
+
+		for {
+			move, ok, err := b.nextMove(ctxTx)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			if err := b.apply(ctxTx, move, dryRun); err != nil {
+				return fmt.Errorf("moving %s from %s to %s: %w", move.PullRequestID, move.FromUserID, move.ToUserID, err)
+			}
+			result.Moves = append(result.Moves, move)
+		}
+
+		result.SpreadAfter = loadSpread(b.loads)
+		result.Loads = b.loads
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// rebalancer хранит нагрузку и открытые ревью участников, обновляя их после каждого переноса
+type rebalancer struct {
+	svc       *Service
+	loads     []domain.ReviewerLoad
+	reviews   map[string][]string
+	prs       map[string]domain.PullRequest
+	tolerance int
+}
+
+// nextMove ищет перенос от самого загруженного участника к самому свободному из тех,
+// кому ревью можно передать. Каждый перенос уменьшает разницу хотя бы на два, поэтому цикл конечен
+func (b *rebalancer) nextMove(ctx context.Context) (domain.RebalanceMove, bool, error) {
+	byLoad := slices.Clone(b.loads)
+	slices.SortFunc(byLoad, func(x, y domain.ReviewerLoad) int {
+		return cmp.Or(cmp.Compare(x.OpenReviews, y.OpenReviews), cmp.Compare(x.UserID, y.UserID))
+	})
+
+	for i := len(byLoad) - 1; i >= 0; i-- {
+		from := byLoad[i]
+		for _, to := range byLoad {
+			if from.OpenReviews-to.OpenReviews <= b.tolerance {
+				break
+			}
+			if !to.HasCapacity() {
+				continue
+			}
+			for _, prID := range b.reviews[from.UserID] {
+				pr := b.prs[prID]
+				if slices.Contains(pr.Reviewers, to.UserID) {
+					continue
+				}
+				err := b.svc.checkReviewerEligible(ctx, pr, to.UserID)
+				if errors.Is(err, domain.ErrNotEligible) {
+					continue
+				}
+				if err != nil {
+					return domain.RebalanceMove{}, false, err
+				}
+				return domain.RebalanceMove{PullRequestID: prID, FromUserID: from.UserID, ToUserID: to.UserID}, true, nil
+			}
+		}
+	}
+	return domain.RebalanceMove{}, false, nil
+}
+
+func (b *rebalancer) apply(ctx context.Context, move domain.RebalanceMove, dryRun bool) error {
+	pr := b.prs[move.PullRequestID]
+	if dryRun {
+		reviewers := slices.DeleteFunc(slices.Clone(pr.Reviewers), func(id string) bool { return id == move.FromUserID })
+		pr.Reviewers = append(reviewers, move.ToUserID)
+	} else {
+		var err error
+		if _, pr, err = b.svc.reassignTo(ctx, pr, move.FromUserID, move.ToUserID); err != nil {
+			return err
+		}
+		if err := b.svc.enqueueNotifications(ctx, reassignedNotification(move.PullRequestID, move.FromUserID, move.ToUserID)); err != nil {
+			return err
+		}
+	}
+	b.prs[move.PullRequestID] = pr
+
+	b.reviews[move.FromUserID] = slices.DeleteFunc(b.reviews[move.FromUserID], func(id string) bool { return id == move.PullRequestID })
+	b.reviews[move.ToUserID] = append(b.reviews[move.ToUserID], move.PullRequestID)
+	for i := range b.loads {
+		switch b.loads[i].UserID {
+		case move.FromUserID:
+			b.loads[i].OpenReviews--
+		case move.ToUserID:
+			b.loads[i].OpenReviews++
+		}
+	}
+	return nil
+}
+
+func loadSpread(loads []domain.ReviewerLoad) int {
+	if len(loads) == 0 {
+		return 0
+	}
+	lo, hi := loads[0].OpenReviews, loads[0].OpenReviews
+	for _, l := range loads[1:] {
+		lo = min(lo, l.OpenReviews)
+		hi = max(hi, l.OpenReviews)
+	}
+	return hi - lo
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_Rebalance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	tName := "rebalance-team"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	for _, id := range []string{"rb_author", "rb_heavy", "rb_light"} {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+	}
+	for _, id := range []string{"rb-pr-1", "rb-pr-2", "rb-pr-3", "rb-pr-4", "rb-pr-merged"} {
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: id, Title: "T", AuthorID: "rb_author", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		require.NoError(t, repo.AddReviewers(ctx, id, []string{"rb_heavy"}))
+	}
+	_, err = svc.MergePR(ctx, "rb-pr-merged")
+	require.NoError(t, err)
+
+	loadsOf := func(result *domain.RebalanceResult) map[string]int {
+		loads := make(map[string]int)
+		for _, l := range result.Loads {
+			loads[l.UserID] = l.OpenReviews
+		}
+		return loads
+	}
+
+	t.Run("RebalanceTeam_DryRun", func(t *testing.T) {
+		result, err := svc.RebalanceTeam(ctx, tName, 1, true)
+
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 4, result.SpreadBefore)
+		require.Len(t, result.Moves, 2)
+		for _, m := range result.Moves {
+			assert.Equal(t, "rb_heavy", m.FromUserID)
+			assert.Equal(t, "rb_light", m.ToUserID)
+		}
+		assert.Equal(t, map[string]int{"rb_author": 0, "rb_heavy": 2, "rb_light": 2}, loadsOf(result))
+		prs, err := svc.ListPRsByReviewer(ctx, "rb_light")
+		require.NoError(t, err)
+		assert.Empty(t, prs)
+	})
+
+	t.Run("RebalanceTeam_DryRunDoesNotLock", func(t *testing.T) {
+		err := svc.runInTx(ctx, func(ctxTx context.Context) error {
+			if _, err := repo.GetPRForUpdate(ctxTx, "rb-pr-1"); err != nil {
+				return err
+			}
+			if _, err := repo.LockReviewerLoads(ctxTx, []string{"rb_author", "rb_heavy", "rb_light"}); err != nil {
+				return err
+			}
+			dryRunCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			result, err := svc.RebalanceTeam(dryRunCtx, tName, 1, true)
+			if err != nil {
+				return err
+			}
+			assert.Len(t, result.Moves, 2)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("RebalanceTeam_Apply", func(t *testing.T) {
+		result, err := svc.RebalanceTeam(ctx, tName, 1, false)
+
+		require.NoError(t, err)
+		require.Len(t, result.Moves, 2)
+		// автор не может ревьюить свои PR, поэтому разница с ним остаётся
+		assert.Equal(t, 2, result.SpreadAfter)
+		prs, err := svc.ListPRsByReviewer(ctx, "rb_light")
+		require.NoError(t, err)
+		assert.Len(t, prs, 2)
+		merged, err := svc.GetPR(ctx, "rb-pr-merged")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"rb_heavy"}, merged.Reviewers)
+	})
+
+	t.Run("RebalanceTeam_WithinTolerance", func(t *testing.T) {
+		result, err := svc.RebalanceTeam(ctx, tName, 2, false)
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Moves)
+	})
+
+	t.Run("RebalanceTeam_UnknownTeam", func(t *testing.T) {
+		_, err := svc.RebalanceTeam(ctx, "rebalance-missing", 1, true)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"reviewer/internal/domain"
+)
+
+func (s *Service) CreateRepository(ctx context.Context, name string, ownerTeams []string) (domain.Repository, error) {
+	var created domain.Repository
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		if _, err := s.repo.CreateRepository(ctxTx, name); err != nil {
+			return err
+		}
+		if err := s.repo.SetRepositoryOwners(ctxTx, name, uniqueStrings(ownerTeams)); err != nil {
+			return err
+		}
+		var err error
+		created, err = s.repo.GetRepository(ctxTx, name)
+		return err
+	})
+	if err != nil {
+		return domain.Repository{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) GetRepository(ctx context.Context, name string) (domain.Repository, error) {
+	return s.repo.GetRepository(ctx, name)
+}
+
+func (s *Service) SetRepositoryOwners(ctx context.Context, name string, ownerTeams []string) (domain.Repository, error) {
+	var updated domain.Repository
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		if _, err := s.repo.GetRepository(ctxTx, name); err != nil {
+			return err
+		}
+		if err := s.repo.SetRepositoryOwners(ctxTx, name, uniqueStrings(ownerTeams)); err != nil {
+			return err
+		}
+		var err error
+		updated, err = s.repo.GetRepository(ctxTx, name)
+		return err
+	})
+	if err != nil {
+		return domain.Repository{}, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_Repository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	for _, team := range []string{"repo-owner", "repo-second", "repo-contrib"} {
+		_, err = svc.CreateTeam(ctx, team)
+		require.NoError(t, err)
+	}
+	_, err = svc.CreateUser(ctx, "ro1", "O1", "repo-owner", true)
+	require.NoError(t, err)
+	_, err = svc.CreateUser(ctx, "rs1", "S1", "repo-second", true)
+	require.NoError(t, err)
+	_, err = svc.CreateUser(ctx, "rc_a", "Author", "repo-contrib", true)
+	require.NoError(t, err)
+	_, err = svc.CreateUser(ctx, "rc1", "C1", "repo-contrib", true)
+	require.NoError(t, err)
+
+	t.Run("CreateRepository_UnknownTeam", func(t *testing.T) {
+		_, err := svc.CreateRepository(ctx, "ghost-repo", []string{"no-such-team"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("CreatePR_ReviewersFromOwningTeams", func(t *testing.T) {
+		created, err := svc.CreateRepository(ctx, "billing", []string{"repo-owner", "repo-second", "repo-owner"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"repo-owner", "repo-second"}, created.OwnerTeams)
+
+		pr, err := svc.CreatePR(ctx, "pr-cross-team", "T", "rc_a", CreatePROptions{Repository: "billing"})
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"ro1", "rs1"}, pr.Reviewers)
+		assert.Equal(t, "billing", pr.Repository)
+		assert.Equal(t, "repo-owner", pr.TeamName)
+	})
+
+	t.Run("CreatePR_UnknownRepository", func(t *testing.T) {
+		_, err := svc.CreatePR(ctx, "pr-no-repo", "T", "rc_a", CreatePROptions{Repository: "nope"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("SetRepositoryOwners_Replaces", func(t *testing.T) {
+		_, err := svc.CreateRepository(ctx, "search", []string{"repo-owner"})
+		require.NoError(t, err)
+
+		updated, err := svc.SetRepositoryOwners(ctx, "search", []string{"repo-second"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"repo-second"}, updated.OwnerTeams)
+	})
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/rules"
+)
+
+// SetRuleSet проверяет набор правил команды и сохраняет его как новую версию.
+// Пустой набор отключает правила, и команда возвращается к встроенным политикам
+func (s *Service) SetRuleSet(ctx context.Context, teamName, content string) (domain.RuleSetVersion, error) {
+	if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+		return domain.RuleSetVersion{}, err
+	}
+	rs, err := rules.Parse(strings.NewReader(content))
+	if err != nil {
+		return domain.RuleSetVersion{}, err
+	}
+	for _, id := range rs.Users() {
+		if _, err := s.repo.GetUser(ctx, id); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.RuleSetVersion{}, fmt.Errorf("%w: unknown user %s", rules.ErrInvalidRules, id)
+			}
+			return domain.RuleSetVersion{}, err
+		}
+	}
+	return s.repo.SaveRuleSet(ctx, teamName, content)
+}
+
+// GetRuleSet возвращает версию набора правил команды (0 — действующую) и список всех версий
+func (s *Service) GetRuleSet(ctx context.Context, teamName string, version int) (domain.RuleSetVersion, []int, error) {
+	if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+		return domain.RuleSetVersion{}, nil, err
+	}
+	v, err := s.repo.GetRuleSet(ctx, teamName, version)
+	if err != nil {
+		return domain.RuleSetVersion{}, nil, err
+	}
+	versions, err := s.repo.ListRuleSetVersions(ctx, teamName)
+	if err != nil {
+		return domain.RuleSetVersion{}, nil, fmt.Errorf("listing rule set versions: %w", err)
+	}
+	return v, versions, nil
+}
+
+// RollbackRuleSet делает действующей прежнюю версию, сохраняя её копию как новую версию
+func (s *Service) RollbackRuleSet(ctx context.Context, teamName string, version int) (domain.RuleSetVersion, error) {
+	var result domain.RuleSetVersion
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		old, err := s.repo.GetRuleSet(ctxTx, teamName, version)
+		if err != nil {
+			return err
+		}
+		result, err = s.repo.SaveRuleSet(ctxTx, teamName, old.Content)
+		return err
+	})
+	return result, err
+}
+
+// activeRuleSet возвращает действующий набор правил команды; nil, если он не загружен или пуст
+func (s *Service) activeRuleSet(ctx context.Context, teamName string) (*rules.RuleSet, int, error) {
+	v, err := s.repo.GetRuleSet(ctx, teamName, 0)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting rule set: %w", err)
+	}
+	rs, err := rules.Parse(strings.NewReader(v.Content))
+	if err != nil {
+		return nil, 0, fmt.Errorf("parsing rule set of team %s v%d: %w", teamName, v.Version, err)
+	}
+	if rs.Empty() {
+		return nil, 0, nil
+	}
+	return rs, v.Version, nil
+}
+
+func ruleCandidate(u domain.User, openReviews int) rules.Candidate {
+	return rules.Candidate{ID: u.ID, Seniority: string(u.Seniority), Expertise: u.ExpertiseTags, OpenReviews: openReviews}
+}
+
+// selectByRuleSet добирает ревьюеров из pool по набору правил команды вместо встроенных политик.
+// matches — уже выбранные владельцы путей, kept — остающиеся на PR ревьюеры, loads — нагрузка кандидатов.
+// Должна вызываться внутри транзакции
+func (s *Service) selectByRuleSet(ctx context.Context, rng *rand.Rand, pr domain.PullRequest, rs *rules.RuleSet, version int,
+	pool []domain.User, excluded map[string]bool, loads reviewerLoads, kept []string, matches []domain.ReviewerMatch, n int,
+) (reviewerSelection, error) {
+	eligible := excludeCandidates(pool, excluded)
+	byID := make(map[string]domain.User, len(eligible))
+	for _, u := range eligible {
+		byID[u.ID] = u
+	}
+	candidates := make([]rules.Candidate, 0, len(eligible))
+	atCapacity := 0
+	for _, u := range eligible {
+		l := loads[u.ID]
+		if !l.HasCapacity() {
+			atCapacity++
+			continue
+		}
+		candidates = append(candidates, ruleCandidate(u, l.OpenReviews))
+	}
+
+	assignedIDs := append(slices.Clone(kept), reviewerSelection{matches: matches}.userIDs()...)
+	assigned := make([]rules.Candidate, 0, len(assignedIDs))
+	for _, id := range assignedIDs {
+		u, err := s.repo.GetUser(ctx, id)
+		if err != nil {
+			return reviewerSelection{}, fmt.Errorf("getting reviewer: %w", err)
+		}
+		assigned = append(assigned, ruleCandidate(u, 0))
+	}
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return reviewerSelection{}, fmt.Errorf("getting author: %w", err)
+	}
+
+	need := n - len(matches)
+	res := rs.Evaluate(rules.PullRequest{
+		AuthorID:        author.ID,
+		AuthorSeniority: string(author.Seniority),
+		Labels:          pr.Labels,
+	}, candidates, assigned, need, rng)
+
+	sel := reviewerSelection{
+		capacityExhausted: len(res.Selected) < need && atCapacity > 0,
+		trace: &domain.RuleTrace{
+			TeamName:    pr.TeamName,
+			Version:     version,
+			Steps:       make([]domain.RuleTraceStep, len(res.Trace)),
+			EvaluatedAt: s.now(),
+		},
+	}
+	for _, id := range res.Selected {
+		matches = append(matches, domain.ReviewerMatch{
+			UserID:      id,
+			Reason:      domain.MatchReasonRuleSet,
+			MatchedTags: matchedTags(byID[id].ExpertiseTags, pr.Labels),
+			Explanation: &domain.AssignmentExplanation{PoolSize: len(candidates), OpenReviews: loads[id].OpenReviews},
+		})
+	}
+	sel.matches = matches
+	for _, name := range res.Unmet {
+		sel.violations = append(sel.violations, domain.ConstraintViolation{Rule: domain.ReviewRuleRuleSet, Message: name})
+	}
+	for i, st := range res.Trace {
+		sel.trace.Steps[i] = domain.RuleTraceStep{Kind: string(st.Kind), Rule: st.Rule, UserID: st.UserID, Detail: st.Detail}
+	}
+	return sel, nil
+}
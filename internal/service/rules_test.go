@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	"reviewer/internal/rules"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_RuleSet(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	tName := "rules-engine"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	for _, id := range []string{"re_author", "re_dba", "re_dev1", "re_dev2", "re_intern"} {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+	}
+	_, err = svc.SetUserExpertise(ctx, "re_dba", []string{"db"})
+	require.NoError(t, err)
+
+	ruleSet := `
+filters:
+  - name: no-intern
+    exclude: { users: [re_intern] }
+requirements:
+  - name: dba-on-db
+    when: { labels_any: [db] }
+    require: { expertise_any: [db] }
+`
+
+	t.Run("SetRuleSet_Invalid", func(t *testing.T) {
+		_, err := svc.SetRuleSet(ctx, tName, "requirements:\n  - count: 1\n")
+
+		assert.ErrorIs(t, err, rules.ErrInvalidRules)
+	})
+
+	t.Run("SetRuleSet_UnknownUser", func(t *testing.T) {
+		_, err := svc.SetRuleSet(ctx, tName, "filters:\n  - name: f\n    exclude: { users: [ghost] }\n")
+
+		assert.ErrorIs(t, err, rules.ErrInvalidRules)
+	})
+
+	t.Run("CreatePR_UsesRuleSet", func(t *testing.T) {
+		v, err := svc.SetRuleSet(ctx, tName, ruleSet)
+		require.NoError(t, err)
+		require.Equal(t, 1, v.Version)
+
+		pr, err := svc.CreatePR(ctx, "re-1", "T", "re_author", CreatePROptions{Labels: []string{"db"}})
+
+		require.NoError(t, err)
+		assert.Contains(t, pr.Reviewers, "re_dba")
+		assert.NotContains(t, pr.Reviewers, "re_intern")
+		assert.Empty(t, pr.Violations)
+		require.NotNil(t, pr.RuleTrace)
+		assert.Equal(t, 1, pr.RuleTrace.Version)
+		assert.Contains(t, pr.RuleTrace.Steps, domain.RuleTraceStep{Kind: "FILTER", Rule: "no-intern", UserID: "re_intern", Detail: "excluded"})
+		for _, m := range pr.Matches {
+			assert.Equal(t, domain.MatchReasonRuleSet, m.Reason)
+		}
+
+		stored, err := svc.GetPR(ctx, "re-1")
+		require.NoError(t, err)
+		require.NotNil(t, stored.RuleTrace)
+		assert.Equal(t, pr.RuleTrace.Steps, stored.RuleTrace.Steps)
+	})
+
+	t.Run("CreatePR_ReportsUnmetRequirement", func(t *testing.T) {
+		inactive := false
+		_, err := svc.UpdateUser(ctx, "re_dba", &inactive)
+		require.NoError(t, err)
+
+		pr, err := svc.CreatePR(ctx, "re-2", "T", "re_author", CreatePROptions{Labels: []string{"db"}})
+
+		require.NoError(t, err)
+		require.Len(t, pr.Violations, 1)
+		assert.Equal(t, domain.ConstraintViolation{Rule: domain.ReviewRuleRuleSet, Message: "dba-on-db"}, pr.Violations[0])
+	})
+
+	t.Run("RollbackRuleSet", func(t *testing.T) {
+		_, err := svc.SetRuleSet(ctx, tName, "")
+		require.NoError(t, err)
+
+		v, err := svc.RollbackRuleSet(ctx, tName, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, v.Version)
+		current, versions, err := svc.GetRuleSet(ctx, tName, 0)
+		require.NoError(t, err)
+		assert.Equal(t, ruleSet, current.Content)
+		assert.Equal(t, []int{1, 2, 3}, versions)
+	})
+}
@@ -0,0 +1,612 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"reviewer/internal/domain"
+)
+
+func excludeCandidates(candidates []domain.User, excluded map[string]bool) []domain.User {
+	result := make([]domain.User, 0, len(candidates))
+	for _, c := range candidates {
+		if !excluded[c.ID] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// normalizeTags приводит теги к нижнему регистру, убирает пустые и повторяющиеся
+func normalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !slices.Contains(result, t) {
+			result = append(result, t)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+func uniqueStrings(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" && !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// newSeededRand возвращает случайное зерно и генератор на его основе. Зерно сохраняется
+// в объяснении назначения, чтобы выбор можно было воспроизвести
+func newSeededRand() (int64, *rand.Rand) {
+	seed := rand.Int64()
+	return seed, rand.New(rand.NewPCG(uint64(seed), 0))
+}
+
+func matchedTags(expertise, labels []string) []string {
+	matched := make([]string, 0)
+	for _, l := range labels {
+		if slices.Contains(expertise, l) {
+			matched = append(matched, l)
+		}
+	}
+	return matched
+}
+
+// pairPenalties возвращает штрафы кандидатов за недавние ревью PR того же автора,
+// если команда PR использует ротацию; при случайном выборе возвращает nil.
+// Вклад каждого назначения линейно убывает до нуля к концу окна ротации
+func (s *Service) pairPenalties(ctx context.Context, team domain.Team, pr domain.PullRequest) (map[string]float64, error) {
+	if team.AssignmentStrategy != domain.AssignmentStrategyRotation {
+		return nil, nil
+	}
+
+	now := s.now()
+	window := time.Duration(team.RotationWindowDays) * 24 * time.Hour
+	history, err := s.repo.ListPairAssignments(ctx, pr.AuthorID, now.Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("listing pair assignments: %w", err)
+	}
+	penalties := make(map[string]float64)
+	for _, a := range history {
+		age := max(now.Sub(a.AssignedAt), 0)
+		penalties[a.ReviewerID] += 1 - float64(age)/float64(window)
+	}
+	return penalties, nil
+}
+
+// pickCandidates выбирает до n пользователей: случайно, если penalties == nil,
+// иначе с наименьшим штрафом (при равных штрафах — случайно)
+func (s *Service) pickCandidates(rng *rand.Rand, users []domain.User, penalties map[string]float64, n int) []domain.User {
+	if penalties == nil {
+		return s.pickRandomReviewers(rng, users, n)
+	}
+	if len(users) == 0 || n <= 0 {
+		return []domain.User{}
+	}
+	ordered := slices.Clone(users)
+	rng.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	slices.SortStableFunc(ordered, func(a, b domain.User) int {
+		return cmp.Compare(penalties[a.ID], penalties[b.ID])
+	})
+	return ordered[:min(n, len(ordered))]
+}
+
+// pickMatchingReviewers выбирает до n ревьюеров, отдавая предпочтение тем,
+// чья экспертиза совпадает с метками PR
+func (s *Service) pickMatchingReviewers(rng *rand.Rand, users []domain.User, labels []string, penalties map[string]float64, n int) []domain.User {
+	if len(labels) == 0 {
+		return s.pickCandidates(rng, users, penalties, n)
+	}
+	var matching, rest []domain.User
+	for _, u := range users {
+		if len(matchedTags(u.ExpertiseTags, labels)) > 0 {
+			matching = append(matching, u)
+		} else {
+			rest = append(rest, u)
+		}
+	}
+	selected := s.pickCandidates(rng, matching, penalties, n)
+	return append(selected, s.pickCandidates(rng, rest, penalties, n-len(selected))...)
+}
+
+// selectionProbabilities возвращает вероятность каждого пользователя попасть в выборку
+// pickMatchingReviewers: кандидаты с меньшим штрафом выбираются раньше, а места, оставшиеся
+// на группу с равным штрафом, делятся в ней поровну
+func selectionProbabilities(users []domain.User, labels []string, penalties map[string]float64, n int) map[string]float64 {
+	var matching, rest []domain.User
+	for _, u := range users {
+		if len(matchedTags(u.ExpertiseTags, labels)) > 0 {
+			matching = append(matching, u)
+		} else {
+			rest = append(rest, u)
+		}
+	}
+	probs := make(map[string]float64, len(users))
+	n = tierProbabilities(probs, matching, penalties, n)
+	tierProbabilities(probs, rest, penalties, n)
+	return probs
+}
+
+// assignmentProbabilities возвращает вероятность каждого кандидата попасть в число n ревьюеров PR
+// при встроенных правилах: по всем исходам выбора владельцев путей, затем senior-ревьюера, если он
+// требуется и среди владельцев его нет, и остальных мест из pool. excluded должен содержать
+// и кандидатов, достигших лимита
+func assignmentProbabilities(paths []string, owners map[string][]pathOwner, loads reviewerLoads, pool []domain.User, excluded map[string]bool,
+	seniorRequired bool, labels []string, penalties map[string]float64, n int,
+) map[string]float64 {
+	probs := make(map[string]float64)
+	for _, u := range excludeCandidates(pool, excluded) {
+		probs[u.ID] = 0
+	}
+	add := func(p float64, part map[string]float64) {
+		for id, q := range part {
+			probs[id] += p * q
+		}
+	}
+	for _, o := range ownerOutcomes(paths, owners, loads, n) {
+		rest := maps.Clone(excluded)
+		for _, u := range o.selected {
+			probs[u.ID] += o.p
+			rest[u.ID] = true
+		}
+		slots := n - len(o.selected)
+		hasSenior := slices.ContainsFunc(o.selected, func(u domain.User) bool { return u.Seniority == domain.SenioritySenior })
+		if !seniorRequired || hasSenior || slots == 0 {
+			add(o.p, selectionProbabilities(excludeCandidates(pool, rest), labels, penalties, slots))
+			continue
+		}
+		seniors := selectionProbabilities(excludeCandidates(usersWithSeniority(pool, domain.SenioritySenior), rest), labels, penalties, 1)
+		if len(seniors) == 0 {
+			add(o.p, selectionProbabilities(excludeCandidates(pool, rest), labels, penalties, slots))
+			continue
+		}
+		for id, q := range seniors {
+			if q == 0 {
+				continue
+			}
+			probs[id] += o.p * q
+			withSenior := maps.Clone(rest)
+			withSenior[id] = true
+			add(o.p*q, selectionProbabilities(excludeCandidates(pool, withSenior), labels, penalties, slots-1))
+		}
+	}
+	return probs
+}
+
+// tierProbabilities заполняет probs для одного яруса кандидатов и возвращает число мест, оставшихся после него
+func tierProbabilities(probs map[string]float64, users []domain.User, penalties map[string]float64, n int) int {
+	ordered := slices.Clone(users)
+	slices.SortFunc(ordered, func(a, b domain.User) int {
+		return cmp.Compare(penalties[a.ID], penalties[b.ID])
+	})
+	for i := 0; i < len(ordered); {
+		j := i + 1
+		for j < len(ordered) && penalties[ordered[j].ID] == penalties[ordered[i].ID] {
+			j++
+		}
+		slots := min(max(n, 0), j-i)
+		for _, u := range ordered[i:j] {
+			probs[u.ID] = float64(slots) / float64(j-i)
+		}
+		n -= slots
+		i = j
+	}
+	return max(n, 0)
+}
+
+func reviewerMatches(users []domain.User, labels []string) []domain.ReviewerMatch {
+	matches := make([]domain.ReviewerMatch, len(users))
+	for i, u := range users {
+		tags := matchedTags(u.ExpertiseTags, labels)
+		reason := domain.MatchReasonRandom
+		if len(tags) > 0 {
+			reason = domain.MatchReasonExpertise
+		}
+		matches[i] = domain.ReviewerMatch{UserID: u.ID, Reason: reason, MatchedTags: tags}
+	}
+	return matches
+}
+
+// reviewerLoads — нагрузка кандидатов на ревью по id пользователя
+type reviewerLoads map[string]domain.ReviewerLoad
+
+// atCapacity возвращает пользователей из users, достигших лимита открытых ревью
+func (l reviewerLoads) atCapacity(users []domain.User) map[string]bool {
+	result := make(map[string]bool)
+	for _, u := range users {
+		if load, ok := l[u.ID]; ok && !load.HasCapacity() {
+			result[u.ID] = true
+		}
+	}
+	return result
+}
+
+// readReviewerLoads читает нагрузку пользователей одним запросом. С lock строки блокируются
+// в порядке id: подбор блокирует всех своих кандидатов разом, и транзакции, подбирающие
+// ревьюеров из пересекающихся наборов, ждут друг друга, а не взаимоблокируются
+func (s *Service) readReviewerLoads(ctx context.Context, userIDs []string, lock bool) (reviewerLoads, error) {
+	ids := slices.Compact(slices.Sorted(slices.Values(userIDs)))
+	result := make(reviewerLoads, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var loads []domain.ReviewerLoad
+	var err error
+	if lock {
+		loads, err = s.repo.LockReviewerLoads(ctx, ids)
+	} else {
+		loads, err = s.repo.GetReviewerLoads(ctx, ids)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading reviewer loads: %w", err)
+	}
+	for _, l := range loads {
+		result[l.UserID] = l
+	}
+	return result, nil
+}
+
+// pickReviewers выбирает до n ревьюеров среди кандидатов, не попавших в excluded,
+// предпочитая кандидатов с экспертизой по меткам labels.
+// Кандидаты, достигшие лимита открытых ревью по loads, пропускаются; capacityExhausted сообщает,
+// что ревьюеров не хватило именно из-за лимита. Штрафы penalties задаёт стратегия ротации.
+// В объяснениях выбранных заполняются размер пула и нагрузка; стратегию и зерно дописывает вызывающий
+func (s *Service) pickReviewers(rng *rand.Rand, candidates []domain.User, excluded map[string]bool, loads reviewerLoads, labels []string, penalties map[string]float64, n int) (matches []domain.ReviewerMatch, capacityExhausted bool) {
+	eligible := excludeCandidates(candidates, excluded)
+	if len(eligible) == 0 || n <= 0 {
+		return []domain.ReviewerMatch{}, false
+	}
+
+	atCapacity := loads.atCapacity(eligible)
+	available := excludeCandidates(eligible, atCapacity)
+	matches = reviewerMatches(s.pickMatchingReviewers(rng, available, labels, penalties, n), labels)
+	for i := range matches {
+		matches[i].Explanation = &domain.AssignmentExplanation{PoolSize: len(available), OpenReviews: loads[matches[i].UserID].OpenReviews}
+	}
+	return matches, len(matches) < n && len(atCapacity) > 0
+}
+
+// reviewExclusions возвращает пользователей, которые не могут ревьюить PR:
+// автора, его руководителей, исключённых правилами для автора и явно исключённых в самом PR
+func (s *Service) reviewExclusions(ctx context.Context, pr domain.PullRequest) (map[string]bool, error) {
+	reasons, err := s.exclusionReasons(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+	excluded := make(map[string]bool, len(reasons))
+	for id := range reasons {
+		excluded[id] = true
+	}
+	return excluded, nil
+}
+
+// exclusionReasons — то же, что reviewExclusions, но с причиной исключения каждого пользователя
+func (s *Service) exclusionReasons(ctx context.Context, pr domain.PullRequest) (map[string]domain.ExclusionReason, error) {
+	conflicts, err := s.repo.ListConflictingReviewers(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, fmt.Errorf("listing conflicting reviewers: %w", err)
+	}
+	reasons := make(map[string]domain.ExclusionReason)
+	for _, id := range conflicts {
+		reasons[id] = domain.ExclusionConflict
+	}
+	for _, id := range pr.ExcludedReviewers {
+		reasons[id] = domain.ExclusionRequested
+	}
+	reasons[pr.AuthorID] = domain.ExclusionAuthor
+	return reasons, nil
+}
+
+// poolTeams возвращает команды, отвечающие за код PR: команды-владельцы репозитория,
+// а если репозиторий не указан — команду PR
+func (s *Service) poolTeams(ctx context.Context, pr domain.PullRequest) ([]string, error) {
+	if pr.Repository == "" {
+		return []string{pr.TeamName}, nil
+	}
+	repo, err := s.repo.GetRepository(ctx, pr.Repository)
+	if err != nil {
+		return nil, fmt.Errorf("getting repository: %w", err)
+	}
+	return repo.OwnerTeams, nil
+}
+
+// candidatePool возвращает активных участников команд, отвечающих за код PR
+func (s *Service) candidatePool(ctx context.Context, pr domain.PullRequest) ([]domain.User, error) {
+	teams, err := s.poolTeams(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := make([]domain.User, 0)
+	for _, team := range teams {
+		members, err := s.repo.GetActiveTeamMembers(ctx, team, s.now())
+		if err != nil {
+			return nil, fmt.Errorf("getting candidates: %w", err)
+		}
+		pool = append(pool, members...)
+	}
+	return pool, nil
+}
+
+// reviewerSelection — результат подбора ревьюеров на PR
+type reviewerSelection struct {
+	matches           []domain.ReviewerMatch
+	capacityExhausted bool
+	violations        []domain.ConstraintViolation
+	trace             *domain.RuleTrace
+	// seed и rng — зерно и генератор, которыми сделан выбор; ими же добираются ревьюеры из запасной команды
+	seed int64
+	rng  *rand.Rand
+	// loads — нагрузка кандидатов, заблокированных при подборе, включая запасную команду
+	loads reviewerLoads
+}
+
+// explain дописывает стратегию и зерно в объяснения назначений, где они ещё не заполнены
+func (sel reviewerSelection) explain(strategy domain.AssignmentStrategy) {
+	for _, m := range sel.matches {
+		if m.Explanation != nil && m.Explanation.Strategy == "" {
+			m.Explanation.Strategy = strategy
+			m.Explanation.Seed = sel.seed
+		}
+	}
+}
+
+func (sel reviewerSelection) userIDs() []string {
+	ids := make([]string, len(sel.matches))
+	for i, m := range sel.matches {
+		ids[i] = m.UserID
+	}
+	return ids
+}
+
+// seniorRequirement сообщает, требуется ли на PR senior-ревьюер, и по какому правилу команды
+func (s *Service) seniorRequirement(ctx context.Context, team domain.Team, pr domain.PullRequest) (domain.ReviewRule, bool, error) {
+	if team.ReviewRules.RequireSenior {
+		return domain.ReviewRuleSeniorReviewer, true, nil
+	}
+	if !team.ReviewRules.JuniorNeedsSenior {
+		return "", false, nil
+	}
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return "", false, fmt.Errorf("getting author: %w", err)
+	}
+	return domain.ReviewRuleJuniorNeedsSenior, author.Seniority == domain.SeniorityJunior, nil
+}
+
+func (s *Service) hasSenior(ctx context.Context, userIDs []string) (bool, error) {
+	for _, id := range userIDs {
+		u, err := s.repo.GetUser(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("getting reviewer: %w", err)
+		}
+		if u.Seniority == domain.SenioritySenior {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func usersWithSeniority(users []domain.User, seniority domain.Seniority) []domain.User {
+	result := make([]domain.User, 0)
+	for _, u := range users {
+		if u.Seniority == seniority {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// selectReviewers подбирает до n ревьюеров на PR: сначала владельцев затронутых путей
+// из любых команд, затем senior-ревьюера, если его требуют правила команды, и остальных
+// кандидатов из pool по стратегии команды PR. Если у команды загружен набор правил,
+// он заменяет встроенные правила и стратегию. kept — ревьюеры, остающиеся на PR.
+// Невыполненные правила команды возвращаются в violations. Все случайные решения принимаются
+// генератором с сохраняемым зерном. Строки владельцев, pool и запасных кандидатов reserve
+// блокируются одним запросом до выбора. Должна вызываться внутри транзакции
+func (s *Service) selectReviewers(ctx context.Context, pr domain.PullRequest, pool, reserve []domain.User, excluded map[string]bool, kept []string, n int) (reviewerSelection, error) {
+	owners, err := s.prOwners(ctx, pr, excluded, n)
+	if err != nil {
+		return reviewerSelection{}, err
+	}
+	ids := ownerIDs(owners)
+	for _, u := range excludeCandidates(slices.Concat(pool, reserve), excluded) {
+		ids = append(ids, u.ID)
+	}
+	loads, err := s.readReviewerLoads(ctx, ids, true)
+	if err != nil {
+		return reviewerSelection{}, err
+	}
+	return s.chooseReviewers(ctx, pr, pool, owners, loads, excluded, kept, n)
+}
+
+// prOwners возвращает владельцев затронутых PR путей, если на PR остались места
+func (s *Service) prOwners(ctx context.Context, pr domain.PullRequest, excluded map[string]bool, n int) (map[string][]pathOwner, error) {
+	if len(pr.ChangedPaths) == 0 || n <= 0 {
+		return nil, nil
+	}
+	return s.resolvePathOwners(ctx, pr.ChangedPaths, excluded)
+}
+
+// chooseReviewers — выбор selectReviewers по уже прочитанной нагрузке loads владельцев owners и кандидатов pool
+func (s *Service) chooseReviewers(ctx context.Context, pr domain.PullRequest, pool []domain.User, owners map[string][]pathOwner, loads reviewerLoads, excluded map[string]bool, kept []string, n int) (reviewerSelection, error) {
+	team, err := s.repo.GetTeamByName(ctx, pr.TeamName)
+	if err != nil {
+		return reviewerSelection{}, fmt.Errorf("getting team: %w", err)
+	}
+	seed, rng := newSeededRand()
+	matches := pickCodeOwners(rng, pr.ChangedPaths, owners, loads, n)
+	penalties, err := s.pairPenalties(ctx, team, pr)
+	if err != nil {
+		return reviewerSelection{}, err
+	}
+
+	rest := make(map[string]bool, len(excluded)+len(matches))
+	for id := range excluded {
+		rest[id] = true
+	}
+	for _, m := range matches {
+		rest[m.UserID] = true
+	}
+
+	ruleSet, version, err := s.activeRuleSet(ctx, team.Name)
+	if err != nil {
+		return reviewerSelection{}, err
+	}
+	if ruleSet != nil {
+		sel, err := s.selectByRuleSet(ctx, rng, pr, ruleSet, version, pool, rest, loads, kept, matches, n)
+		if err != nil {
+			return reviewerSelection{}, err
+		}
+		sel.seed, sel.rng, sel.loads = seed, rng, loads
+		sel.explain(domain.AssignmentStrategyRuleSet)
+		return sel, nil
+	}
+
+	sel := reviewerSelection{seed: seed, rng: rng, loads: loads}
+	rule, required, err := s.seniorRequirement(ctx, team, pr)
+	if err != nil {
+		return reviewerSelection{}, err
+	}
+	if required {
+		found, err := s.hasSenior(ctx, append(slices.Clone(kept), reviewerSelection{matches: matches}.userIDs()...))
+		if err != nil {
+			return reviewerSelection{}, err
+		}
+		message := "no reviewer slot left for a senior"
+		if !found && len(matches) < n {
+			senior, exhausted := s.pickReviewers(rng, usersWithSeniority(pool, domain.SenioritySenior), rest, loads, pr.Labels, penalties, 1)
+			if len(senior) > 0 {
+				found = true
+				rest[senior[0].UserID] = true
+				senior[0].Reason = domain.MatchReasonSeniority
+				matches = append(matches, senior[0])
+			}
+			message = "no available senior reviewer"
+			if exhausted {
+				message = "all senior reviewers are at capacity"
+			}
+		}
+		if !found {
+			sel.violations = append(sel.violations, domain.ConstraintViolation{Rule: rule, Message: message})
+		}
+	}
+
+	selected, capacityExhausted := s.pickReviewers(rng, pool, rest, loads, pr.Labels, penalties, n-len(matches))
+	sel.matches = append(matches, selected...)
+	sel.capacityExhausted = capacityExhausted
+	sel.explain(team.AssignmentStrategy)
+	return sel, nil
+}
+
+// saveSelection сохраняет выбранных ревьюеров PR и трассировку набора правил, если он применялся
+func (s *Service) saveSelection(ctx context.Context, prID string, sel reviewerSelection) error {
+	if err := s.repo.AddMatchedReviewers(ctx, prID, sel.matches); err != nil {
+		return err
+	}
+	notifications := make([]domain.Notification, len(sel.matches))
+	for i, m := range sel.matches {
+		notifications[i] = assignedNotification(prID, m.UserID)
+	}
+	if err := s.enqueueNotifications(ctx, notifications...); err != nil {
+		return err
+	}
+	if sel.trace == nil {
+		return nil
+	}
+	return s.repo.SaveRuleTrace(ctx, prID, *sel.trace)
+}
+
+// assignShadowReviewer добавляет на PR junior-стажёра из pool, если этого требуют правила команды.
+// Стажёр не считается ревьюером PR и не учитывается в лимитах открытых ревью.
+// Возвращает нарушение, если подходящего стажёра нет
+func (s *Service) assignShadowReviewer(ctx context.Context, rng *rand.Rand, pr domain.PullRequest, pool []domain.User, excluded map[string]bool) (*domain.ConstraintViolation, error) {
+	team, err := s.repo.GetTeamByName(ctx, pr.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("getting team: %w", err)
+	}
+	if !team.ReviewRules.ShadowReviewer {
+		return nil, nil
+	}
+	juniors := excludeCandidates(usersWithSeniority(pool, domain.SeniorityJunior), excluded)
+	if len(juniors) == 0 {
+		return &domain.ConstraintViolation{Rule: domain.ReviewRuleShadowReviewer, Message: "no available junior for the shadow slot"}, nil
+	}
+	shadow := s.pickRandomReviewers(rng, juniors, 1)[0]
+	if err := s.repo.AddShadowReviewer(ctx, pr.ID, shadow.ID); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// fillReviewers добирает на PR до n новых ревьюеров из команд, отвечающих за PR, а при нехватке — из запасной команды.
+// Должна вызываться внутри транзакции, заблокировавшей PR
+func (s *Service) fillReviewers(ctx context.Context, pr domain.PullRequest, n int, fallbackTeam string, exclude []string) (reviewerSelection, error) {
+	if n <= 0 {
+		return reviewerSelection{}, nil
+	}
+
+	excluded, err := s.reviewExclusions(ctx, pr)
+	if err != nil {
+		return reviewerSelection{}, err
+	}
+	for _, id := range pr.Reviewers {
+		excluded[id] = true
+	}
+	for _, id := range exclude {
+		excluded[id] = true
+	}
+
+	candidates, err := s.candidatePool(ctx, pr)
+	if err != nil {
+		return reviewerSelection{}, err
+	}
+	var fallback []domain.User
+	if fallbackTeam != "" && fallbackTeam != pr.TeamName {
+		fallback, err = s.repo.GetActiveTeamMembers(ctx, fallbackTeam, s.now())
+		if err != nil {
+			return reviewerSelection{}, fmt.Errorf("getting fallback candidates: %w", err)
+		}
+	}
+	sel, err := s.selectReviewers(ctx, pr, candidates, fallback, excluded, pr.Reviewers, n)
+	if err != nil {
+		return reviewerSelection{}, err
+	}
+
+	if len(sel.matches) < n && len(fallback) > 0 {
+		for _, m := range sel.matches {
+			excluded[m.UserID] = true
+		}
+		team, err := s.repo.GetTeamByName(ctx, pr.TeamName)
+		if err != nil {
+			return reviewerSelection{}, fmt.Errorf("getting team: %w", err)
+		}
+		penalties, err := s.pairPenalties(ctx, team, pr)
+		if err != nil {
+			return reviewerSelection{}, err
+		}
+		more, fallbackExhausted := s.pickReviewers(sel.rng, fallback, excluded, sel.loads, pr.Labels, penalties, n-len(sel.matches))
+		sel.matches = append(sel.matches, more...)
+		sel.capacityExhausted = sel.capacityExhausted || fallbackExhausted
+		sel.explain(team.AssignmentStrategy)
+	}
+
+	if err := s.saveSelection(ctx, pr.ID, sel); err != nil {
+		return reviewerSelection{}, err
+	}
+	sel.capacityExhausted = sel.capacityExhausted && len(sel.matches) < n
+	return sel, nil
+}
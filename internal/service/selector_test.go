@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_ReviewRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+	svc := New(repo)
+
+	tName := "rules-team"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	seniority := map[string]domain.Seniority{
+		"rr_author": domain.SeniorityJunior,
+		"rr_senior": domain.SenioritySenior,
+		"rr_mid1":   domain.SeniorityMid,
+		"rr_mid2":   domain.SeniorityMid,
+		"rr_junior": domain.SeniorityJunior,
+	}
+	for id, level := range seniority {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+		_, err = svc.SetUserSeniority(ctx, id, level)
+		require.NoError(t, err)
+	}
+	zero := 0
+	_, err = svc.SetUserCapacity(ctx, "rr_junior", &zero)
+	require.NoError(t, err)
+	team, err := svc.SetTeamReviewRules(ctx, tName, domain.TeamReviewRules{JuniorNeedsSenior: true, ShadowReviewer: true})
+	require.NoError(t, err)
+	require.True(t, team.ReviewRules.JuniorNeedsSenior)
+
+	t.Run("CreatePR_JuniorGetsSeniorAndShadow", func(t *testing.T) {
+		pr, err := svc.CreatePR(ctx, "rr-1", "T", "rr_author", CreatePROptions{})
+
+		require.NoError(t, err)
+		assert.Contains(t, pr.Reviewers, "rr_senior")
+		assert.Len(t, pr.Reviewers, 2)
+		assert.Equal(t, []string{"rr_junior"}, pr.ShadowReviewers)
+		assert.Empty(t, pr.Violations)
+	})
+
+	t.Run("CreatePR_ReportsSeniorViolation", func(t *testing.T) {
+		_, err := svc.SetUserCapacity(ctx, "rr_senior", &zero)
+		require.NoError(t, err)
+
+		pr, err := svc.CreatePR(ctx, "rr-2", "T", "rr_author", CreatePROptions{})
+
+		require.NoError(t, err)
+		assert.NotContains(t, pr.Reviewers, "rr_senior")
+		require.Len(t, pr.Violations, 1)
+		assert.Equal(t, domain.ReviewRuleJuniorNeedsSenior, pr.Violations[0].Rule)
+		assert.Equal(t, []string{"rr_junior"}, pr.ShadowReviewers)
+	})
+}
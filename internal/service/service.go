@@ -1,28 +1,79 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"math/rand/v2"
+	"time"
 
 	"reviewer/internal/domain"
+	"reviewer/internal/notify"
 	"reviewer/internal/repository"
 )
 
 type Service struct {
-	repo repository.Repository
+	repo                    repository.Repository
+	absenceReleaseThreshold time.Duration
+	now                     func() time.Time
+	notifier                notify.Notifier
+	digestHour              int
 }
 
-func New(repo repository.Repository) *Service {
-	return &Service{repo: repo}
+type Option func(*Service)
+
+// WithAbsenceReleaseThreshold включает автоматическое снятие ревью с пользователя,
+// когда начинается его отсутствие не короче d. Нулевое значение отключает снятие
+func WithAbsenceReleaseThreshold(d time.Duration) Option {
+	return func(s *Service) {
+		s.absenceReleaseThreshold = d
+	}
+}
+
+// WithNotifier задаёт канал доставки уведомлений; по умолчанию они отбрасываются
+func WithNotifier(n notify.Notifier) Option {
+	return func(s *Service) {
+		s.notifier = n
+	}
+}
+
+// WithDigestHour задаёт местный час получателя, начиная с которого ему отправляется сводка за день
+func WithDigestHour(hour int) Option {
+	return func(s *Service) {
+		s.digestHour = hour
+	}
+}
+
+// WithClock подменяет источник текущего времени, например в тестах окон отсутствия
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+func New(repo repository.Repository, opts ...Option) *Service {
+	s := &Service{repo: repo, now: time.Now, notifier: notify.Nop{}, digestHour: DefaultDigestHour}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Service) runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	txRepo, ok := s.repo.(repository.Transactor)
+	if !ok {
+		return errors.New("repository does not support transactions")
+	}
+	return txRepo.RunInTx(ctx, fn)
 }
 
 // Вспомогательная функция (общая)
-func (s *Service) pickRandomReviewers(users []domain.User, n int) []domain.User {
+func (s *Service) pickRandomReviewers(rng *rand.Rand, users []domain.User, n int) []domain.User {
 	if len(users) == 0 || n <= 0 {
 		return []domain.User{}
 	}
 	shuffled := make([]domain.User, len(users))
 	copy(shuffled, users)
-	rand.Shuffle(len(shuffled), func(i, j int) {
+	rng.Shuffle(len(shuffled), func(i, j int) {
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
 	if n > len(shuffled) {
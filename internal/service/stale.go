@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"reviewer/internal/domain"
+)
+
+// staleReviewLockKey — ключ advisory-блокировки, по которой экземпляры сервиса выбирают,
+// кто проверяет просроченные ревью
+const staleReviewLockKey int64 = 0x5245564945570001
+
+// EscalateStaleReviews напоминает ревьюерам, не отреагировавшим на PR за срок напоминания
+// команды, и переназначает ревью, просроченные дольше срока переназначения; если переназначить
+// не на кого, ревьюер получает напоминание. Если проверку уже выполняет другой экземпляр,
+// возвращается результат с Skipped
+func (s *Service) EscalateStaleReviews(ctx context.Context) (*domain.EscalationResult, error) {
+	result := &domain.EscalationResult{
+		Reminded:    []domain.ReviewAssignment{},
+		Escalations: []domain.Escalation{},
+	}
+	now := s.now()
+
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		acquired, err := s.repo.TryAdvisoryLock(ctxTx, staleReviewLockKey)
+		if err != nil {
+			return fmt.Errorf("acquiring stale review lock: %w", err)
+		}
+		if !acquired {
+			result.Skipped = true
+			return nil
+		}
+
+		stale, err := s.repo.ListStaleReviews(ctxTx, now)
+		if err != nil {
+			return fmt.Errorf("listing stale reviews: %w", err)
+		}
+		for _, r := range stale {
+			age := now.Sub(r.AssignedAt)
+			escalated := false
+			if escalationDue(r, now) {
+				esc := domain.Escalation{PullRequestID: r.PullRequestID, ReviewerID: r.ReviewerID}
+				_, newReviewer, err := s.reassignReviewer(ctxTx, r.PullRequestID, r.ReviewerID, ReassignOptions{})
+				switch {
+				case err == nil:
+					escalated = true
+					esc.NewReviewerID = newReviewer.ID
+					if err := s.enqueueNotifications(ctxTx, domain.Notification{
+						UserID:        r.ReviewerID,
+						Kind:          domain.NotificationReviewEscalated,
+						PullRequestID: r.PullRequestID,
+						Text:          fmt.Sprintf("Review of %s was reassigned to %s after %d hours without a response", r.PullRequestID, newReviewer.ID, *r.SLA.EscalationHours),
+					}); err != nil {
+						return err
+					}
+				case isReassignFailure(err):
+					esc.Error = err.Error()
+					if err := s.repo.MarkEscalationFailed(ctxTx, r.PullRequestID, r.ReviewerID, now); err != nil {
+						return fmt.Errorf("marking failed escalation: %w", err)
+					}
+				default:
+					return fmt.Errorf("escalating %s of %s: %w", r.PullRequestID, r.ReviewerID, err)
+				}
+				result.Escalations = append(result.Escalations, esc)
+			}
+			if !escalated && r.RemindedAt == nil && overdue(age, r.SLA.ReminderHours) {
+				if err := s.repo.MarkReminded(ctxTx, r.PullRequestID, r.ReviewerID, now); err != nil {
+					return fmt.Errorf("marking reminder: %w", err)
+				}
+				result.Reminded = append(result.Reminded, domain.ReviewAssignment{PullRequestID: r.PullRequestID, UserID: r.ReviewerID})
+				if err := s.enqueueNotifications(ctxTx, domain.Notification{
+					UserID:        r.ReviewerID,
+					Kind:          domain.NotificationReviewReminder,
+					PullRequestID: r.PullRequestID,
+					Text:          fmt.Sprintf("Review of %s is waiting for you for %d hours", r.PullRequestID, int(age.Hours())),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// escalationDue сообщает, пора ли переназначить ревью. После неудачной попытки следующая
+// делается не раньше, чем через срок переназначения команды
+func escalationDue(r domain.StaleReview, now time.Time) bool {
+	if !overdue(now.Sub(r.AssignedAt), r.SLA.EscalationHours) {
+		return false
+	}
+	return r.EscalationFailedAt == nil || overdue(now.Sub(*r.EscalationFailedAt), r.SLA.EscalationHours)
+}
+
+func overdue(age time.Duration, hours *int) bool {
+	return hours != nil && age >= time.Duration(*hours)*time.Hour
+}
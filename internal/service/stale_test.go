@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"reviewer/internal/domain"
+	"reviewer/internal/repository"
+	"reviewer/internal/repository/postgres"
+	testpg "reviewer/internal/tests/postgres"
+)
+
+func TestService_StaleReviews(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	connStr, teardown, err := testpg.Setup(ctx)
+	require.NoError(t, err)
+	defer teardown()
+
+	repo, err := postgres.New(ctx, connStr)
+	require.NoError(t, err)
+	defer repo.(interface{ Close() }).Close()
+
+	now := time.Now()
+	notifier := &recordingNotifier{}
+	svc := New(repo, WithClock(func() time.Time { return now }), WithNotifier(notifier))
+
+	tName := "stale-team"
+	_, err = svc.CreateTeam(ctx, tName)
+	require.NoError(t, err)
+	for _, id := range []string{"st_author", "st_slow", "st_fast", "st_spare"} {
+		_, err = svc.CreateUser(ctx, id, id, tName, true)
+		require.NoError(t, err)
+	}
+	reminder, escalation := 24, 72
+	_, err = svc.SetTeamReviewSLA(ctx, tName, domain.TeamReviewSLA{ReminderHours: &reminder, EscalationHours: &escalation})
+	require.NoError(t, err)
+	_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "st-pr", Title: "T", AuthorID: "st_author", TeamName: tName, Status: domain.PRStatusOpen})
+	require.NoError(t, err)
+	require.NoError(t, repo.AddReviewers(ctx, "st-pr", []string{"st_slow", "st_fast"}))
+
+	t.Run("SubmitReview", func(t *testing.T) {
+		review, err := svc.SubmitReview(ctx, "st-pr", "st_fast", domain.ReviewDecisionCommented)
+
+		require.NoError(t, err)
+		assert.NotZero(t, review.ID)
+		assert.Equal(t, domain.ReviewDecisionCommented, review.Decision)
+	})
+
+	t.Run("SubmitReview_NotAssigned", func(t *testing.T) {
+		_, err := svc.SubmitReview(ctx, "st-pr", "st_spare", domain.ReviewDecisionApproved)
+
+		assert.ErrorIs(t, err, domain.ErrNotAssigned)
+	})
+
+	t.Run("EscalateStaleReviews_WithinSLA", func(t *testing.T) {
+		result, err := svc.EscalateStaleReviews(ctx)
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Reminded)
+		assert.Empty(t, result.Escalations)
+	})
+
+	t.Run("EscalateStaleReviews_Reminder", func(t *testing.T) {
+		now = time.Now().Add(25 * time.Hour)
+
+		result, err := svc.EscalateStaleReviews(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ReviewAssignment{{PullRequestID: "st-pr", UserID: "st_slow"}}, result.Reminded)
+		_, err = svc.DeliverNotifications(ctx)
+		require.NoError(t, err)
+		reminders := notifier.ofKind(domain.NotificationReviewReminder)
+		require.Len(t, reminders, 1)
+		assert.Equal(t, "st_slow", reminders[0].UserID)
+
+		again, err := svc.EscalateStaleReviews(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, again.Reminded)
+	})
+
+	t.Run("EscalateStaleReviews_Skipped", func(t *testing.T) {
+		err := repo.(repository.Transactor).RunInTx(ctx, func(ctxTx context.Context) error {
+			acquired, err := repo.TryAdvisoryLock(ctxTx, staleReviewLockKey)
+			require.NoError(t, err)
+			require.True(t, acquired)
+
+			result, err := svc.EscalateStaleReviews(ctx)
+
+			require.NoError(t, err)
+			assert.True(t, result.Skipped)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("EscalateStaleReviews_Reassigns", func(t *testing.T) {
+		now = time.Now().Add(73 * time.Hour)
+
+		result, err := svc.EscalateStaleReviews(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, result.Escalations, 1)
+		assert.Equal(t, domain.Escalation{PullRequestID: "st-pr", ReviewerID: "st_slow", NewReviewerID: "st_spare"}, result.Escalations[0])
+		pr, err := svc.GetPR(ctx, "st-pr")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"st_fast", "st_spare"}, pr.Reviewers)
+		_, err = svc.DeliverNotifications(ctx)
+		require.NoError(t, err)
+		escalated := notifier.ofKind(domain.NotificationReviewEscalated)
+		require.Len(t, escalated, 1)
+		assert.Equal(t, "st_slow", escalated[0].UserID)
+	})
+
+	t.Run("EscalateStaleReviews_NoCandidatesRemindsOnce", func(t *testing.T) {
+		lonely := "stale-lonely-team"
+		_, err := svc.CreateTeam(ctx, lonely)
+		require.NoError(t, err)
+		for _, id := range []string{"stl_author", "stl_slow"} {
+			_, err = svc.CreateUser(ctx, id, id, lonely, true)
+			require.NoError(t, err)
+		}
+		_, err = svc.SetTeamReviewSLA(ctx, lonely, domain.TeamReviewSLA{ReminderHours: &reminder, EscalationHours: &escalation})
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "stl-pr", Title: "T", AuthorID: "stl_author", TeamName: lonely, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		require.NoError(t, repo.AddReviewers(ctx, "stl-pr", []string{"stl_slow"}))
+		now = time.Now().Add(73 * time.Hour)
+		// ревью на st-pr тоже просрочены к этому моменту, поэтому смотрим только на stl-pr
+		escalationsOf := func(result *domain.EscalationResult) []domain.Escalation {
+			var escs []domain.Escalation
+			for _, e := range result.Escalations {
+				if e.PullRequestID == "stl-pr" {
+					escs = append(escs, e)
+				}
+			}
+			return escs
+		}
+		remindersSent := func() int {
+			_, err := svc.DeliverNotifications(ctx)
+			require.NoError(t, err)
+			n := 0
+			for _, msg := range notifier.ofKind(domain.NotificationReviewReminder) {
+				if msg.PullRequestID == "stl-pr" {
+					n++
+				}
+			}
+			return n
+		}
+
+		result, err := svc.EscalateStaleReviews(ctx)
+
+		require.NoError(t, err)
+		escs := escalationsOf(result)
+		require.Len(t, escs, 1)
+		assert.Equal(t, "stl_slow", escs[0].ReviewerID)
+		assert.NotEmpty(t, escs[0].Error)
+		assert.Contains(t, result.Reminded, domain.ReviewAssignment{PullRequestID: "stl-pr", UserID: "stl_slow"})
+		assert.Equal(t, 1, remindersSent())
+
+		again, err := svc.EscalateStaleReviews(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, escalationsOf(again))
+		assert.NotContains(t, again.Reminded, domain.ReviewAssignment{PullRequestID: "stl-pr", UserID: "stl_slow"})
+		assert.Equal(t, 1, remindersSent())
+	})
+}
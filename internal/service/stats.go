@@ -6,6 +6,43 @@ import (
 	"reviewer/internal/domain"
 )
 
-func (s *Service) ReviewerStats(ctx context.Context) ([]domain.UserAssignmentStats, error) {
-	return s.repo.GetReviewerStats(ctx)
+// AssignmentStats считает назначения по истории назначений, поэтому в статистику попадают
+// и ревьюеры, которых потом переназначили или сняли. По умолчанию группирует по ревьюерам
+func (s *Service) AssignmentStats(ctx context.Context, filter domain.AssignmentStatsFilter) ([]domain.AssignmentStats, error) {
+	if filter.TeamName != "" {
+		if _, err := s.repo.GetTeamByName(ctx, filter.TeamName); err != nil {
+			return nil, err
+		}
+	}
+	if filter.GroupBy == "" {
+		filter.GroupBy = domain.StatsGroupByUser
+	}
+	return s.repo.GetAssignmentStats(ctx, filter)
+}
+
+// PairStats возвращает историю назначений по парам автор→ревьюер; teamName ограничивает авторов командой
+func (s *Service) PairStats(ctx context.Context, teamName string) ([]domain.PairStats, error) {
+	if teamName != "" {
+		if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.GetPairStats(ctx, teamName)
+}
+
+// LatencyStats возвращает перцентили скорости ревью и слияния по командам и по ревьюерам
+// для PR, созданных в окне filter
+func (s *Service) LatencyStats(ctx context.Context, filter domain.LatencyFilter) (teams, reviewers []domain.LatencyStats, err error) {
+	if filter.TeamName != "" {
+		if _, err := s.repo.GetTeamByName(ctx, filter.TeamName); err != nil {
+			return nil, nil, err
+		}
+	}
+	if teams, err = s.repo.GetTeamLatency(ctx, filter); err != nil {
+		return nil, nil, err
+	}
+	if reviewers, err = s.repo.GetReviewerLatency(ctx, filter); err != nil {
+		return nil, nil, err
+	}
+	return teams, reviewers, nil
 }
@@ -2,11 +2,14 @@ package service
 
 import (
 	"context"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"reviewer/internal/domain"
 	"reviewer/internal/repository/postgres"
 	testpg "reviewer/internal/tests/postgres"
 )
@@ -32,9 +35,9 @@ func TestService_Stats(t *testing.T) {
 		_, err = svc.CreateUser(ctx, "u1", "U1", tName, true)
 		require.NoError(t, err)
 
-		pr1, err := svc.CreatePR(ctx, "pr1", "Title", "u1")
+		pr1, err := svc.CreatePR(ctx, "pr1", "Title", "u1", CreatePROptions{})
 		require.NoError(t, err)
-		pr2, err := svc.CreatePR(ctx, "pr2", "Title", "u1")
+		pr2, err := svc.CreatePR(ctx, "pr2", "Title", "u1", CreatePROptions{})
 		require.NoError(t, err)
 
 		err = repo.AddReviewers(ctx, pr1.ID, []string{"u1"})
@@ -42,11 +45,163 @@ func TestService_Stats(t *testing.T) {
 		err = repo.AddReviewers(ctx, pr2.ID, []string{"u1"})
 		require.NoError(t, err)
 
-		stats, err := svc.ReviewerStats(ctx)
+		stats, err := svc.AssignmentStats(ctx, domain.AssignmentStatsFilter{})
 
 		require.NoError(t, err)
 		require.Len(t, stats, 1)
 		assert.Equal(t, "u1", stats[0].UserID)
 		assert.Equal(t, int64(2), stats[0].AssignmentCount)
 	})
+
+	t.Run("AssignmentStats_KeepsReassigned", func(t *testing.T) {
+		tName := "stats-reassign"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"sr_author", "sr_a", "sr_b", "sr_c"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+		pr, err := svc.CreatePR(ctx, "sr-1", "T", "sr_author", CreatePROptions{})
+		require.NoError(t, err)
+		_, _, err = svc.ReassignReviewer(ctx, pr.ID, pr.Reviewers[0], ReassignOptions{})
+		require.NoError(t, err)
+
+		stats, err := svc.AssignmentStats(ctx, domain.AssignmentStatsFilter{TeamName: tName, GroupBy: domain.StatsGroupByTeam})
+
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, int64(3), stats[0].AssignmentCount)
+		assert.Equal(t, int64(1), stats[0].RemovedCount)
+	})
+
+	t.Run("AssignmentStats_UnknownTeam", func(t *testing.T) {
+		_, err := svc.AssignmentStats(ctx, domain.AssignmentStatsFilter{TeamName: "stats-ghost"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("LatencyStats", func(t *testing.T) {
+		tName := "latency-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"lt_author", "lt_a", "lt_b"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+		_, err = svc.CreatePR(ctx, "lt-1", "T", "lt_author", CreatePROptions{})
+		require.NoError(t, err)
+		_, err = svc.CreatePR(ctx, "lt-2", "T", "lt_author", CreatePROptions{})
+		require.NoError(t, err)
+		_, err = svc.SubmitReview(ctx, "lt-1", "lt_a", domain.ReviewDecisionCommented)
+		require.NoError(t, err)
+		for _, id := range []string{"lt_a", "lt_b"} {
+			_, err = svc.SubmitReview(ctx, "lt-1", id, domain.ReviewDecisionApproved)
+			require.NoError(t, err)
+		}
+		_, err = svc.MergePR(ctx, "lt-1")
+		require.NoError(t, err)
+
+		teams, reviewers, err := svc.LatencyStats(ctx, domain.LatencyFilter{TeamName: tName})
+
+		require.NoError(t, err)
+		require.Len(t, teams, 1)
+		team := teams[0]
+		assert.Equal(t, tName, team.TeamName)
+		// у lt-2 нет ни ревью, ни слияния
+		assert.Equal(t, int64(1), team.TimeToFirstReview.Count)
+		assert.Equal(t, int64(1), team.TimeToApproval.Count)
+		assert.Equal(t, int64(1), team.TimeToMerge.Count)
+		require.NotNil(t, team.TimeToMerge.P50)
+		assert.GreaterOrEqual(t, *team.TimeToMerge.P50, 0.0)
+		assert.LessOrEqual(t, *team.TimeToFirstReview.P99, *team.TimeToMerge.P99)
+
+		require.Len(t, reviewers, 2)
+		for _, r := range reviewers {
+			assert.Equal(t, int64(1), r.TimeToFirstReview.Count)
+			assert.Equal(t, int64(1), r.TimeToApproval.Count)
+			assert.LessOrEqual(t, *r.TimeToFirstReview.P50, *r.TimeToApproval.P50)
+		}
+		assert.Equal(t, "lt_a", reviewers[0].UserID)
+	})
+
+	t.Run("LatencyStats_Window", func(t *testing.T) {
+		from := time.Now().Add(time.Hour)
+
+		teams, reviewers, err := svc.LatencyStats(ctx, domain.LatencyFilter{From: &from, TeamName: "latency-svc"})
+
+		require.NoError(t, err)
+		assert.Empty(t, teams)
+		assert.Empty(t, reviewers)
+	})
+
+	t.Run("LatencyStats_UnknownTeam", func(t *testing.T) {
+		_, _, err := svc.LatencyStats(ctx, domain.LatencyFilter{TeamName: "latency-ghost"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("RotationAvoidsRecentPairs", func(t *testing.T) {
+		tName := "rotation-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"rot_author", "rot_a", "rot_b", "rot_c"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+		_, err = svc.SetTeamStrategy(ctx, tName, domain.AssignmentStrategyRotation, 14)
+		require.NoError(t, err)
+		first, err := svc.CreatePR(ctx, "rot-1", "T", "rot_author", CreatePROptions{})
+		require.NoError(t, err)
+		require.Len(t, first.Reviewers, 2)
+
+		second, err := svc.CreatePR(ctx, "rot-2", "T", "rot_author", CreatePROptions{})
+
+		require.NoError(t, err)
+		for _, id := range []string{"rot_a", "rot_b", "rot_c"} {
+			if !slices.Contains(first.Reviewers, id) {
+				assert.Contains(t, second.Reviewers, id)
+			}
+		}
+		pairs, err := svc.PairStats(ctx, tName)
+		require.NoError(t, err)
+		var total int64
+		for _, p := range pairs {
+			assert.Equal(t, "rot_author", p.AuthorID)
+			total += p.AssignmentCount
+		}
+		assert.Equal(t, int64(4), total)
+	})
+	t.Run("AssignmentExplanation", func(t *testing.T) {
+		tName := "explain-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		for _, id := range []string{"ex_author", "ex_a", "ex_b", "ex_c"} {
+			_, err = svc.CreateUser(ctx, id, id, tName, true)
+			require.NoError(t, err)
+		}
+		zero := 0
+		_, err = svc.SetUserCapacity(ctx, "ex_c", &zero)
+		require.NoError(t, err)
+		created, err := svc.CreatePR(ctx, "ex-1", "T", "ex_author", CreatePROptions{})
+		require.NoError(t, err)
+		require.Len(t, created.Reviewers, 2)
+
+		pr, err := svc.GetPR(ctx, "ex-1")
+
+		require.NoError(t, err)
+		require.Len(t, pr.Matches, 2)
+		seed := pr.Matches[0].Explanation.Seed
+		for _, m := range pr.Matches {
+			require.NotNil(t, m.Explanation)
+			assert.Equal(t, domain.AssignmentStrategyRandom, m.Explanation.Strategy)
+			assert.Equal(t, 2, m.Explanation.PoolSize)
+			assert.Equal(t, 0, m.Explanation.OpenReviews)
+			assert.Equal(t, seed, m.Explanation.Seed)
+		}
+		history, err := svc.GetAssignmentHistory(ctx, "ex-1")
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, domain.MatchReasonRandom, history[0].Reason)
+		assert.Equal(t, seed, history[0].Explanation.Seed)
+	})
 }
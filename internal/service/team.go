@@ -2,11 +2,9 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"reviewer/internal/domain"
-	"reviewer/internal/repository"
 )
 
 func (s *Service) CreateTeam(ctx context.Context, name string) (domain.Team, error) {
@@ -33,22 +31,76 @@ func (s *Service) GetTeamByName(ctx context.Context, name string) (domain.Team,
 	return team, nil
 }
 
-func (s *Service) DeactivateTeamAndRemoveReviews(ctx context.Context, teamName string) (*domain.DeactivationResult, error) {
+func (s *Service) SetTeamCapacity(ctx context.Context, name string, defaultMaxOpenReviews *int) (domain.Team, error) {
+	if _, err := s.repo.SetTeamCapacity(ctx, name, defaultMaxOpenReviews); err != nil {
+		return domain.Team{}, err
+	}
+	return s.GetTeamByName(ctx, name)
+}
+
+// SetTeamReviewRules заменяет правила состава ревьюеров команды
+func (s *Service) SetTeamReviewRules(ctx context.Context, name string, rules domain.TeamReviewRules) (domain.Team, error) {
+	if _, err := s.repo.SetTeamReviewRules(ctx, name, rules); err != nil {
+		return domain.Team{}, err
+	}
+	return s.GetTeamByName(ctx, name)
+}
+
+// SetTeamReviewSLA задаёт сроки напоминания и переназначения для ревью PR команды
+func (s *Service) SetTeamReviewSLA(ctx context.Context, name string, sla domain.TeamReviewSLA) (domain.Team, error) {
+	if _, err := s.repo.SetTeamReviewSLA(ctx, name, sla); err != nil {
+		return domain.Team{}, err
+	}
+	return s.GetTeamByName(ctx, name)
+}
+
+// SetTeamLead назначает руководителя команды; им может быть только участник команды.
+// Пустой leadID снимает руководителя
+func (s *Service) SetTeamLead(ctx context.Context, name string, leadID *string) (domain.Team, error) {
+	if leadID != nil {
+		lead, err := s.repo.GetUser(ctx, *leadID)
+		if err != nil {
+			return domain.Team{}, fmt.Errorf("getting lead: %w", err)
+		}
+		if lead.TeamName != name {
+			return domain.Team{}, domain.ErrNotTeamMember
+		}
+	}
+	if _, err := s.repo.SetTeamLead(ctx, name, leadID); err != nil {
+		return domain.Team{}, err
+	}
+	return s.GetTeamByName(ctx, name)
+}
+
+// SetTeamStrategy задаёт стратегию выбора ревьюеров команды и окно ротации в днях
+func (s *Service) SetTeamStrategy(ctx context.Context, name string, strategy domain.AssignmentStrategy, windowDays int) (domain.Team, error) {
+	if _, err := s.repo.SetTeamStrategy(ctx, name, strategy, windowDays); err != nil {
+		return domain.Team{}, err
+	}
+	return s.GetTeamByName(ctx, name)
+}
+
+type DeactivationOptions struct {
+	Reassign     bool
+	FallbackTeam string
+}
+
+func (s *Service) DeactivateTeamAndRemoveReviews(ctx context.Context, teamName string, opts DeactivationOptions) (*domain.DeactivationResult, error) {
 	if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
 		return nil, err
 	}
+	if opts.FallbackTeam != "" {
+		if _, err := s.repo.GetTeamByName(ctx, opts.FallbackTeam); err != nil {
+			return nil, fmt.Errorf("getting fallback team: %w", err)
+		}
+	}
 
 	result := &domain.DeactivationResult{
 		DeactivatedUsers: []domain.User{},
 		AffectedPRs:      []domain.PullRequestShort{},
 	}
 
-	txRepo, ok := s.repo.(repository.Transactor)
-	if !ok {
-		return nil, errors.New("repository does not support transactions")
-	}
-
-	err := txRepo.RunInTx(ctx, func(ctxTx context.Context) error {
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
 		deactivatedUsers, err := s.repo.DeactivateTeamMembers(ctxTx, teamName)
 		if err != nil {
 			return fmt.Errorf("deactivating members: %w", err)
@@ -64,12 +116,35 @@ func (s *Service) DeactivateTeamAndRemoveReviews(ctx context.Context, teamName s
 			deactivatedUserIDs[i] = u.ID
 		}
 
+		removedReviews, err := s.repo.ListOpenReviewAssignments(ctxTx, deactivatedUserIDs)
+		if err != nil {
+			return fmt.Errorf("listing open reviews: %w", err)
+		}
+
 		affectedPRs, err := s.repo.RemoveReviewersFromOpenPRs(ctxTx, deactivatedUserIDs)
 		if err != nil {
 			return fmt.Errorf("removing reviewers from open PRs: %w", err)
 		}
 		result.AffectedPRs = affectedPRs
 
+		op, err := s.repo.CreateOperation(ctxTx, &domain.Operation{
+			Kind:           domain.OperationTeamDeactivation,
+			TeamName:       teamName,
+			UserIDs:        deactivatedUserIDs,
+			RemovedReviews: removedReviews,
+		})
+		if err != nil {
+			return fmt.Errorf("saving operation: %w", err)
+		}
+		result.OperationID = op.ID
+
+		if opts.Reassign {
+			result.Reassignments, err = s.replaceRemovedReviewers(ctxTx, removedReviews, opts.FallbackTeam)
+			if err != nil {
+				return fmt.Errorf("reassigning reviewers: %w", err)
+			}
+		}
+
 		return nil
 	})
 
@@ -79,3 +154,56 @@ func (s *Service) DeactivateTeamAndRemoveReviews(ctx context.Context, teamName s
 
 	return result, nil
 }
+
+func (s *Service) ReactivateTeam(ctx context.Context, teamName string) (*domain.UndoResult, error) {
+	if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	var result *domain.UndoResult
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		op, err := s.repo.GetLastPendingOperationForUpdate(ctxTx, teamName, domain.OperationTeamDeactivation)
+		if err != nil {
+			return err
+		}
+		result, err = s.undoOperation(ctxTx, op)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Service) replaceRemovedReviewers(ctx context.Context, removed []domain.ReviewAssignment, fallbackTeam string) ([]domain.PRReassignment, error) {
+	removedByPR := make(map[string][]string)
+	prOrder := make([]string, 0)
+	for _, rv := range removed {
+		if _, ok := removedByPR[rv.PullRequestID]; !ok {
+			prOrder = append(prOrder, rv.PullRequestID)
+		}
+		removedByPR[rv.PullRequestID] = append(removedByPR[rv.PullRequestID], rv.UserID)
+	}
+
+	reassignments := make([]domain.PRReassignment, 0, len(prOrder))
+	for _, prID := range prOrder {
+		pr, err := s.repo.GetPRForUpdate(ctx, prID)
+		if err != nil {
+			return nil, err
+		}
+		removedIDs := removedByPR[prID]
+		sel, err := s.fillReviewers(ctx, pr, len(removedIDs), fallbackTeam, removedIDs)
+		if err != nil {
+			return nil, err
+		}
+		reassignments = append(reassignments, domain.PRReassignment{
+			PullRequestID:     prID,
+			Removed:           removedIDs,
+			Replacements:      sel.userIDs(),
+			Understaffed:      len(sel.matches) < len(removedIDs),
+			CapacityExhausted: sel.capacityExhausted,
+			Violations:        sel.violations,
+		})
+	}
+	return reassignments, nil
+}
@@ -60,10 +60,10 @@ func TestService_Team(t *testing.T) {
 		_, err = svc.CreateUser(ctx, "d3", "D3", tName, true)
 		require.NoError(t, err)
 
-		_, err := svc.CreatePR(ctx, "pr-deact", "Title", "d1")
+		_, err := svc.CreatePR(ctx, "pr-deact", "Title", "d1", CreatePROptions{})
 		require.NoError(t, err)
 
-		res, err := svc.DeactivateTeamAndRemoveReviews(ctx, tName)
+		res, err := svc.DeactivateTeamAndRemoveReviews(ctx, tName, DeactivationOptions{})
 
 		require.NoError(t, err)
 		assert.Len(t, res.DeactivatedUsers, 3)
@@ -75,8 +75,65 @@ func TestService_Team(t *testing.T) {
 		assert.NotContains(t, updatedPR.Reviewers, "d2")
 	})
 
+	t.Run("DeactivateTeam_ReassignFromFallback", func(t *testing.T) {
+		tName := "deact-reassign"
+		fallback := "deact-fallback"
+		_, err = svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateTeam(ctx, fallback)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "dr_a", "A", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "dr_r1", "R1", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "dr_r2", "R2", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "df_1", "F1", fallback, true)
+		require.NoError(t, err)
+		_, err = svc.CreatePR(ctx, "pr-deact-re", "T", "dr_a", CreatePROptions{})
+		require.NoError(t, err)
+
+		res, err := svc.DeactivateTeamAndRemoveReviews(ctx, tName, DeactivationOptions{Reassign: true, FallbackTeam: fallback})
+
+		require.NoError(t, err)
+		require.Len(t, res.Reassignments, 1)
+		re := res.Reassignments[0]
+		assert.Equal(t, "pr-deact-re", re.PullRequestID)
+		assert.ElementsMatch(t, []string{"dr_r1", "dr_r2"}, re.Removed)
+		assert.Equal(t, []string{"df_1"}, re.Replacements)
+		assert.True(t, re.Understaffed)
+		updatedPR, _ := repo.GetPR(ctx, "pr-deact-re")
+		assert.Equal(t, []string{"df_1"}, updatedPR.Reviewers)
+	})
+
+	t.Run("DeactivateTeam_UnknownFallback", func(t *testing.T) {
+		_, err := svc.DeactivateTeamAndRemoveReviews(ctx, "deact-team", DeactivationOptions{Reassign: true, FallbackTeam: "unknown-team"})
+
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("ReactivateTeam_UndoesLastDeactivation", func(t *testing.T) {
+		tName := "react-team"
+		_, err = svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ra1", "A1", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "ra2", "A2", tName, true)
+		require.NoError(t, err)
+		deact, err := svc.DeactivateTeamAndRemoveReviews(ctx, tName, DeactivationOptions{})
+		require.NoError(t, err)
+
+		res, err := svc.ReactivateTeam(ctx, tName)
+
+		require.NoError(t, err)
+		assert.Equal(t, deact.OperationID, res.OperationID)
+		assert.Len(t, res.ReactivatedUsers, 2)
+		_, err = svc.ReactivateTeam(ctx, tName)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
 	t.Run("DeactivateTeam_NotFound", func(t *testing.T) {
-		_, err := svc.DeactivateTeamAndRemoveReviews(ctx, "unknown-team")
+		_, err := svc.DeactivateTeamAndRemoveReviews(ctx, "unknown-team", DeactivationOptions{})
 
 		require.Error(t, err)
 		assert.Equal(t, domain.ErrNotFound, err)
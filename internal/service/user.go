@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"reviewer/internal/domain"
 )
@@ -13,11 +14,107 @@ func (s *Service) CreateUser(ctx context.Context, id, username, teamName string,
 		TeamName: teamName,
 		IsActive: isActive,
 	}
-	return s.repo.CreateUser(ctx, user)
+	if !isActive {
+		return s.repo.CreateUser(ctx, user)
+	}
+
+	var created domain.User
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		var err error
+		created, err = s.repo.CreateUser(ctxTx, user)
+		if err != nil {
+			return err
+		}
+		if _, err := s.backfillTeam(ctxTx, created.TeamName); err != nil {
+			return fmt.Errorf("backfilling team: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	return created, nil
 }
 
 func (s *Service) UpdateUser(ctx context.Context, id string, isActive *bool) (domain.User, error) {
-	return s.repo.UpdateUser(ctx, id, isActive)
+	if isActive == nil || !*isActive {
+		return s.repo.UpdateUser(ctx, id, isActive)
+	}
+
+	var updated domain.User
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		var err error
+		updated, err = s.repo.UpdateUser(ctxTx, id, isActive)
+		if err != nil {
+			return err
+		}
+		if _, err := s.backfillTeam(ctxTx, updated.TeamName); err != nil {
+			return fmt.Errorf("backfilling team: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeactivateUserAndReassignReviews(ctx context.Context, id string) (*domain.DeactivationResult, error) {
+	result := &domain.DeactivationResult{
+		DeactivatedUsers: []domain.User{},
+		AffectedPRs:      []domain.PullRequestShort{},
+		Reassignments:    []domain.PRReassignment{},
+	}
+
+	err := s.runInTx(ctx, func(ctxTx context.Context) error {
+		isActive := false
+		user, err := s.repo.UpdateUser(ctxTx, id, &isActive)
+		if err != nil {
+			return err
+		}
+		result.DeactivatedUsers = []domain.User{user}
+
+		result.AffectedPRs, result.Reassignments, err = s.releaseUserReviews(ctxTx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// releaseUserReviews снимает пользователя со всех открытых PR и подбирает ему замену
+func (s *Service) releaseUserReviews(ctx context.Context, userID string) ([]domain.PullRequestShort, []domain.PRReassignment, error) {
+	removedReviews, err := s.repo.ListOpenReviewAssignments(ctx, []string{userID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing open reviews: %w", err)
+	}
+	if len(removedReviews) == 0 {
+		return []domain.PullRequestShort{}, []domain.PRReassignment{}, nil
+	}
+
+	affectedPRs, err := s.repo.RemoveReviewersFromOpenPRs(ctx, []string{userID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("removing reviewer from open PRs: %w", err)
+	}
+
+	reassignments, err := s.replaceRemovedReviewers(ctx, removedReviews, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("reassigning reviewers: %w", err)
+	}
+	return affectedPRs, reassignments, nil
+}
+
+func (s *Service) SetUserCapacity(ctx context.Context, id string, maxOpenReviews *int) (domain.User, error) {
+	return s.repo.SetUserCapacity(ctx, id, maxOpenReviews)
+}
+
+func (s *Service) SetUserExpertise(ctx context.Context, id string, tags []string) (domain.User, error) {
+	return s.repo.SetUserExpertise(ctx, id, normalizeTags(tags))
+}
+
+func (s *Service) SetUserSeniority(ctx context.Context, id string, seniority domain.Seniority) (domain.User, error) {
+	return s.repo.SetUserSeniority(ctx, id, seniority)
 }
 
 func (s *Service) ListPRsByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequestShort, error) {
@@ -56,6 +56,41 @@ func TestService_User(t *testing.T) {
 		require.ErrorIs(t, err, domain.ErrNotFound)
 	})
 
+	t.Run("DeactivateUserAndReassignReviews", func(t *testing.T) {
+		tName := "deact-user-svc"
+		_, err := svc.CreateTeam(ctx, tName)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "du_a", "A", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "du_old", "Old", tName, true)
+		require.NoError(t, err)
+		_, err = svc.CreateUser(ctx, "du_new", "New", tName, true)
+		require.NoError(t, err)
+		_, err = repo.CreatePR(ctx, &domain.PullRequest{ID: "pr-du", Title: "T", AuthorID: "du_a", TeamName: tName, Status: domain.PRStatusOpen})
+		require.NoError(t, err)
+		err = repo.AddReviewers(ctx, "pr-du", []string{"du_old"})
+		require.NoError(t, err)
+
+		res, err := svc.DeactivateUserAndReassignReviews(ctx, "du_old")
+
+		require.NoError(t, err)
+		require.Len(t, res.DeactivatedUsers, 1)
+		assert.False(t, res.DeactivatedUsers[0].IsActive)
+		require.Len(t, res.AffectedPRs, 1)
+		assert.Equal(t, "pr-du", res.AffectedPRs[0].ID)
+		require.Len(t, res.Reassignments, 1)
+		assert.Equal(t, []string{"du_new"}, res.Reassignments[0].Replacements)
+		assert.False(t, res.Reassignments[0].Understaffed)
+		pr, _ := repo.GetPR(ctx, "pr-du")
+		assert.Equal(t, []string{"du_new"}, pr.Reviewers)
+	})
+
+	t.Run("DeactivateUserAndReassignReviews_NotFound", func(t *testing.T) {
+		_, err := svc.DeactivateUserAndReassignReviews(ctx, "unknown")
+
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
 	t.Run("ListPRsByReviewer", func(t *testing.T) {
 		tName := "list-pr-svc"
 		_, err := repo.CreateTeam(ctx, tName)
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE operations (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('TEAM_DEACTIVATION')),
+    team_name TEXT NOT NULL REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    user_ids TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    undone_at TIMESTAMPTZ
+);
+
+CREATE TABLE operation_removed_reviews (
+    operation_id BIGINT NOT NULL REFERENCES operations(id) ON DELETE CASCADE,
+    pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (operation_id, pr_id, user_id)
+);
+
+CREATE INDEX idx_operations_team_kind ON operations(team_name, kind, created_at DESC);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS operation_removed_reviews;
+DROP TABLE IF EXISTS operations;
+-- +goose StatementEnd
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pull_requests
+    ADD COLUMN needed_reviewers INT NOT NULL DEFAULT 2 CHECK (needed_reviewers >= 0);
+
+CREATE INDEX idx_pull_requests_team_status ON pull_requests(team_name, status);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pull_requests_team_status;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS needed_reviewers;
+-- +goose StatementEnd
\ No newline at end of file
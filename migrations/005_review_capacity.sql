@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN max_open_reviews INT CHECK (max_open_reviews >= 0);
+
+ALTER TABLE teams
+    ADD COLUMN default_max_open_reviews INT CHECK (default_max_open_reviews >= 0);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP COLUMN IF EXISTS default_max_open_reviews;
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
+-- +goose StatementEnd
\ No newline at end of file
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE team_codeowners (
+    team_name TEXT PRIMARY KEY REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE pull_requests
+    ADD COLUMN changed_paths TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE pr_reviewers
+    ADD COLUMN match_reason TEXT,
+    ADD COLUMN matched_tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN rule_team TEXT,
+    ADD COLUMN rule_line INT,
+    ADD COLUMN rule_pattern TEXT,
+    ADD COLUMN matched_path TEXT;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS matched_path,
+    DROP COLUMN IF EXISTS rule_pattern,
+    DROP COLUMN IF EXISTS rule_line,
+    DROP COLUMN IF EXISTS rule_team,
+    DROP COLUMN IF EXISTS matched_tags,
+    DROP COLUMN IF EXISTS match_reason;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS changed_paths;
+DROP TABLE IF EXISTS team_codeowners;
+-- +goose StatementEnd
\ No newline at end of file
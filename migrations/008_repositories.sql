@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE repositories (
+    name TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE repository_owners (
+    repository_name TEXT NOT NULL REFERENCES repositories(name) ON DELETE CASCADE,
+    team_name TEXT NOT NULL REFERENCES teams(name) ON UPDATE CASCADE ON DELETE RESTRICT,
+    position INT NOT NULL,
+    PRIMARY KEY (repository_name, team_name)
+);
+
+ALTER TABLE pull_requests
+    ADD COLUMN repository TEXT REFERENCES repositories(name) ON DELETE RESTRICT;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS repository;
+DROP TABLE IF EXISTS repository_owners;
+DROP TABLE IF EXISTS repositories;
+-- +goose StatementEnd
\ No newline at end of file
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN manager_id TEXT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE review_exclusions (
+    id BIGSERIAL PRIMARY KEY,
+    reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    author_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (reviewer_id, author_id),
+    CHECK (reviewer_id <> author_id)
+);
+
+CREATE INDEX idx_review_exclusions_author ON review_exclusions(author_id);
+
+ALTER TABLE pull_requests
+    ADD COLUMN excluded_reviewers TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS excluded_reviewers;
+DROP TABLE IF EXISTS review_exclusions;
+ALTER TABLE users DROP COLUMN IF EXISTS manager_id;
+-- +goose StatementEnd
\ No newline at end of file
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+    ADD COLUMN assignment_strategy TEXT NOT NULL DEFAULT 'RANDOM' CHECK (assignment_strategy IN ('RANDOM', 'ROTATION')),
+    ADD COLUMN rotation_window_days INT NOT NULL DEFAULT 30 CHECK (rotation_window_days > 0);
+
+CREATE TABLE assignment_history (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    author_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_assignment_history_author ON assignment_history(author_id, assigned_at);
+
+INSERT INTO assignment_history (pr_id, author_id, reviewer_id, assigned_at)
+SELECT r.pr_id, p.author_id, r.user_id, r.created_at
+FROM pr_reviewers r
+JOIN pull_requests p ON p.id = r.pr_id;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS assignment_history;
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS rotation_window_days,
+    DROP COLUMN IF EXISTS assignment_strategy;
+-- +goose StatementEnd
\ No newline at end of file
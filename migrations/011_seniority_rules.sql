@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN seniority TEXT CHECK (seniority IN ('JUNIOR', 'MID', 'SENIOR'));
+
+ALTER TABLE teams
+    ADD COLUMN require_senior BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN junior_needs_senior BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN shadow_reviewer BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE pr_shadow_reviewers (
+    pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (pr_id, user_id)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pr_shadow_reviewers;
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS shadow_reviewer,
+    DROP COLUMN IF EXISTS junior_needs_senior,
+    DROP COLUMN IF EXISTS require_senior;
+ALTER TABLE users DROP COLUMN IF EXISTS seniority;
+-- +goose StatementEnd
\ No newline at end of file
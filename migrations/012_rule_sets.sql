@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE team_rule_sets (
+    team_name TEXT NOT NULL REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    version INT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (team_name, version)
+);
+
+CREATE TABLE pr_rule_traces (
+    pr_id TEXT PRIMARY KEY REFERENCES pull_requests(id) ON DELETE CASCADE,
+    team_name TEXT NOT NULL,
+    version INT NOT NULL,
+    steps JSONB NOT NULL,
+    evaluated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pr_rule_traces;
+DROP TABLE IF EXISTS team_rule_sets;
+-- +goose StatementEnd
\ No newline at end of file
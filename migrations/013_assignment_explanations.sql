@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pr_reviewers
+    ADD COLUMN strategy TEXT,
+    ADD COLUMN pool_size INT,
+    ADD COLUMN open_reviews INT,
+    ADD COLUMN rng_seed BIGINT;
+
+ALTER TABLE assignment_history
+    ADD COLUMN match_reason TEXT,
+    ADD COLUMN strategy TEXT,
+    ADD COLUMN pool_size INT,
+    ADD COLUMN open_reviews INT,
+    ADD COLUMN rng_seed BIGINT;
+
+CREATE INDEX idx_assignment_history_pr ON assignment_history(pr_id, assigned_at);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_assignment_history_pr;
+ALTER TABLE assignment_history
+    DROP COLUMN IF EXISTS rng_seed,
+    DROP COLUMN IF EXISTS open_reviews,
+    DROP COLUMN IF EXISTS pool_size,
+    DROP COLUMN IF EXISTS strategy,
+    DROP COLUMN IF EXISTS match_reason;
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS rng_seed,
+    DROP COLUMN IF EXISTS open_reviews,
+    DROP COLUMN IF EXISTS pool_size,
+    DROP COLUMN IF EXISTS strategy;
+-- +goose StatementEnd
\ No newline at end of file
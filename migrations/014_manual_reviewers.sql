@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+    ADD COLUMN lead_id TEXT REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE assignment_history
+    ADD COLUMN action TEXT NOT NULL DEFAULT 'ASSIGNED' CHECK (action IN ('ASSIGNED', 'REMOVED')),
+    ADD COLUMN actor_id TEXT REFERENCES users(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM assignment_history WHERE action <> 'ASSIGNED';
+ALTER TABLE assignment_history
+    DROP COLUMN IF EXISTS actor_id,
+    DROP COLUMN IF EXISTS action;
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS lead_id;
+-- +goose StatementEnd
\ No newline at end of file
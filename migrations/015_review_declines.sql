@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE assignment_history
+    DROP CONSTRAINT assignment_history_action_check,
+    ADD CONSTRAINT assignment_history_action_check CHECK (action IN ('ASSIGNED', 'REMOVED', 'DECLINED')),
+    ADD COLUMN decline_reason TEXT CHECK (decline_reason IN ('BUSY', 'LACKS_CONTEXT', 'CONFLICT')),
+    ADD CONSTRAINT assignment_history_decline_action_check CHECK ((action = 'DECLINED') = (decline_reason IS NOT NULL));
+
+CREATE INDEX idx_assignment_history_reviewer ON assignment_history(reviewer_id, action);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_assignment_history_reviewer;
+DELETE FROM assignment_history WHERE action = 'DECLINED';
+ALTER TABLE assignment_history
+    DROP CONSTRAINT IF EXISTS assignment_history_decline_action_check,
+    DROP COLUMN IF EXISTS decline_reason,
+    DROP CONSTRAINT assignment_history_action_check,
+    ADD CONSTRAINT assignment_history_action_check CHECK (action IN ('ASSIGNED', 'REMOVED'));
+-- +goose StatementEnd
\ No newline at end of file
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+    ADD COLUMN reminder_hours INT CHECK (reminder_hours > 0),
+    ADD COLUMN escalation_hours INT CHECK (escalation_hours > 0),
+    ADD CONSTRAINT teams_escalation_after_reminder_check
+        CHECK (reminder_hours IS NULL OR escalation_hours IS NULL OR escalation_hours > reminder_hours);
+
+ALTER TABLE pr_reviewers
+    ADD COLUMN reminded_at TIMESTAMPTZ,
+    ADD COLUMN escalation_failed_at TIMESTAMPTZ;
+
+CREATE TABLE review_decisions (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    decision TEXT NOT NULL CHECK (decision IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_review_decisions_pr_reviewer ON review_decisions(pr_id, reviewer_id, created_at);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS review_decisions;
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS escalation_failed_at,
+    DROP COLUMN IF EXISTS reminded_at;
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_escalation_after_reminder_check,
+    DROP COLUMN IF EXISTS escalation_hours,
+    DROP COLUMN IF EXISTS reminder_hours;
+-- +goose StatementEnd
\ No newline at end of file
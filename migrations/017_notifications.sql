@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE notification_preferences (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL DEFAULT 'LOG' CHECK (channel IN ('SLACK', 'EMAIL', 'LOG', 'NONE')),
+    address TEXT NOT NULL DEFAULT '',
+    delivery TEXT NOT NULL DEFAULT 'IMMEDIATE' CHECK (delivery IN ('IMMEDIATE', 'DIGEST')),
+    quiet_start SMALLINT CHECK (quiet_start BETWEEN 0 AND 23),
+    quiet_end SMALLINT CHECK (quiet_end BETWEEN 0 AND 23),
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    CONSTRAINT notification_preferences_quiet_hours_check CHECK ((quiet_start IS NULL) = (quiet_end IS NULL))
+);
+
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    pr_id TEXT REFERENCES pull_requests(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+CREATE INDEX idx_notifications_pending ON notifications(id) WHERE sent_at IS NULL;
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_preferences;
+-- +goose StatementEnd
\ No newline at end of file
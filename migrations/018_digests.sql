@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification_preferences
+    ADD COLUMN digest_sent_at TIMESTAMPTZ;
+
+CREATE INDEX idx_pull_requests_author ON pull_requests(author_id, status);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pull_requests_author;
+ALTER TABLE notification_preferences
+    DROP COLUMN IF EXISTS digest_sent_at;
+-- +goose StatementEnd
\ No newline at end of file